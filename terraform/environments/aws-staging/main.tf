@@ -0,0 +1,24 @@
+terraform {
+  required_version = ">= 1.0"
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~>5.80.0"
+    }
+  }
+}
+
+provider "aws" {
+  region = var.region
+}
+
+# Example VPC, the primary resource checked by the AWS tests.
+# State is local until an S3 backend is provisioned for this environment.
+resource "aws_vpc" "main" {
+  cidr_block = var.cidr_block
+
+  tags = merge(var.tags, {
+    Name        = var.name
+    Environment = "staging"
+  })
+} 
\ No newline at end of file
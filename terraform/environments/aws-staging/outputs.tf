@@ -0,0 +1,9 @@
+output "vpc_id" {
+  description = "ID of the created VPC"
+  value       = aws_vpc.main.id
+}
+
+output "vpc_name" {
+  description = "Name of the created VPC"
+  value       = aws_vpc.main.tags["Name"]
+} 
\ No newline at end of file
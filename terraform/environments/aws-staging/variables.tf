@@ -0,0 +1,26 @@
+variable "name" {
+  description = "Name of the VPC"
+  type        = string
+  default     = "staging-vpc"
+}
+
+variable "region" {
+  description = "AWS region for resources"
+  type        = string
+  default     = "us-east-1"
+}
+
+variable "cidr_block" {
+  description = "CIDR block of the VPC"
+  type        = string
+  default     = "10.0.0.0/16"
+}
+
+variable "tags" {
+  description = "Tags to apply to resources"
+  type        = map(string)
+  default = {
+    Environment = "staging"
+    ManagedBy   = "terraform"
+  }
+} 
\ No newline at end of file
@@ -10,16 +10,16 @@ terraform {
 
 provider "azurerm" {
   features {}
-  use_oidc = true
+  use_oidc                        = true
   resource_provider_registrations = "none"
 }
 
 # Example resource group using the module
 module "example" {
   source = "../../modules/example"
-  
+
   resource_group_name = var.resource_group_name
-  location           = var.location
-  environment        = "staging"
-  tags               = var.tags
+  location            = var.location
+  environment         = "staging"
+  tags                = var.tags
 } 
\ No newline at end of file
@@ -1,7 +1,16 @@
 resource "azurerm_resource_group" "main" {
   name     = var.resource_group_name
   location = var.location
-  tags     = merge(var.tags, {
+  tags = merge(var.tags, {
     Environment = var.environment
   })
+}
+
+resource "azurerm_management_lock" "main" {
+  count = var.lock_level == null ? 0 : 1
+
+  name       = "${var.resource_group_name}-lock"
+  scope      = azurerm_resource_group.main.id
+  lock_level = var.lock_level
+  notes      = "Protects the ${var.environment} resource group from accidental deletion"
 } 
\ No newline at end of file
@@ -1,11 +1,21 @@
 variable "resource_group_name" {
   description = "Name of the resource group"
   type        = string
+
+  validation {
+    condition     = length(var.resource_group_name) >= 1 && length(var.resource_group_name) <= 90
+    error_message = "resource_group_name must be 1 to 90 characters long."
+  }
 }
 
 variable "location" {
   description = "Azure region for the resource group"
   type        = string
+
+  validation {
+    condition     = contains(["eastus", "eastus2", "westus2", "northeurope", "westeurope"], lower(replace(var.location, " ", "")))
+    error_message = "location must be one of the approved regions: eastus, eastus2, westus2, northeurope, westeurope."
+  }
 }
 
 variable "environment" {
@@ -17,4 +27,15 @@ variable "tags" {
   description = "Tags to apply to the resource group"
   type        = map(string)
   default     = {}
+}
+
+variable "lock_level" {
+  description = "Management lock to place on the resource group: CanNotDelete, ReadOnly, or null for none"
+  type        = string
+  default     = null
+
+  validation {
+    condition     = var.lock_level == null ? true : contains(["CanNotDelete", "ReadOnly"], var.lock_level)
+    error_message = "lock_level must be CanNotDelete, ReadOnly or null."
+  }
 } 
\ No newline at end of file
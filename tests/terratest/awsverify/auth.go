@@ -0,0 +1,43 @@
+package awsverify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// authTimeout bounds the preflight identity request, which can otherwise
+// wait on instance metadata endpoints that do not exist outside AWS.
+const authTimeout = 30 * time.Second
+
+// CheckAuthE resolves the region under test and calls STS GetCallerIdentity
+// to prove the configured credentials work.
+func CheckAuthE() (string, error) {
+	region := Region()
+	sess, err := NewSession(region)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), authTimeout)
+	defer cancel()
+	if _, err := sts.New(sess).GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{}); err != nil {
+		return "", wrapAuthError(err)
+	}
+	return region, nil
+}
+
+// RequireAWSAuth skips the test with an explicit message unless working AWS
+// credentials are available, and returns the region to pass to the other
+// helpers in this package.
+func RequireAWSAuth(t *testing.T) string {
+	t.Helper()
+
+	region, err := CheckAuthE()
+	if err != nil {
+		t.Skipf("no usable AWS credentials: %v", err)
+	}
+	return region
+}
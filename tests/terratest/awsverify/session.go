@@ -0,0 +1,73 @@
+// Package awsverify queries AWS directly to confirm that the resources
+// Terraform reports as created actually exist in the expected state. It
+// mirrors azureverify for environments that deploy to AWS.
+package awsverify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// defaultRegion is used when neither AWS_REGION nor AWS_DEFAULT_REGION is
+// set.
+const defaultRegion = "us-east-1"
+
+// Region returns the region targeted by the aws provider, preferring
+// AWS_REGION over AWS_DEFAULT_REGION.
+func Region() string {
+	if region := firstEnv("AWS_REGION", "AWS_DEFAULT_REGION"); region != "" {
+		return region
+	}
+	return defaultRegion
+}
+
+// NewSession builds an AWS session for region from the same sources the aws
+// provider reads: the AWS_* variables, the shared config and credentials
+// files, and instance or web identity roles such as the one assumed by
+// aws-actions/configure-aws-credentials.
+func NewSession(region string) (*session.Session, error) {
+	if region == "" {
+		return nil, errors.New("region is empty; set AWS_REGION or AWS_DEFAULT_REGION")
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            aws.Config{Region: aws.String(region)},
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, wrapAuthError(err)
+	}
+	return sess, nil
+}
+
+// wrapAuthError turns credential failures into an error that points at the
+// environment configuration rather than at the resource being queried.
+func wrapAuthError(err error) error {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) || isCredentialError(awsErr.Code()) {
+		return fmt.Errorf("authenticating to AWS (check AWS_* credentials or the shared config): %w", err)
+	}
+	return err
+}
+
+func isCredentialError(code string) bool {
+	switch code {
+	case "NoCredentialProviders", "ExpiredToken", "ExpiredTokenException",
+		"InvalidClientTokenId", "UnrecognizedClientException", "AuthFailure":
+		return true
+	}
+	return false
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package awsverify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// MissingTagsE returns the required tag keys absent from the VPC. Unlike
+// Azure, AWS tag keys are case-sensitive, but keys are compared
+// case-insensitively so the same required list serves both clouds.
+func MissingTagsE(region, vpcID string, required []string) ([]string, error) {
+	vpc, err := GetVPCE(region, vpcID)
+	if err != nil {
+		return nil, err
+	}
+	return missingKeys(vpc.Tags, required), nil
+}
+
+// AssertRequiredTags fails the test if the VPC lacks one of the required tag
+// keys.
+func AssertRequiredTags(t *testing.T, region, vpcID string, required []string) {
+	t.Helper()
+
+	missing, err := MissingTagsE(region, vpcID, required)
+	if err != nil {
+		t.Fatalf("checking tags on VPC %q: %v", vpcID, err)
+	}
+	if len(missing) > 0 {
+		t.Errorf("VPC %q is missing required tags: %s", vpcID, strings.Join(missing, ", "))
+	}
+}
+
+func missingKeys(tags []*ec2.Tag, required []string) []string {
+	present := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		present[strings.ToLower(aws.StringValue(tag.Key))] = true
+	}
+
+	var missing []string
+	for _, key := range required {
+		if !present[strings.ToLower(key)] {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,85 @@
+package awsverify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// ErrVPCNotFound is returned, wrapped, by GetVPCE when the VPC does not
+// exist.
+var ErrVPCNotFound = errors.New("VPC not found")
+
+// vpcGoneTimeout bounds how long AssertVPCGone waits for EC2 to stop
+// reporting a VPC that Terraform destroyed.
+const (
+	vpcGoneTimeout  = 5 * time.Minute
+	vpcGoneInterval = 10 * time.Second
+)
+
+// GetVPCE fetches a VPC by ID from EC2.
+func GetVPCE(region, vpcID string) (*ec2.Vpc, error) {
+	sess, err := NewSession(region)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ec2.New(sess).DescribeVpcsWithContext(context.Background(), &ec2.DescribeVpcsInput{
+		VpcIds: []*string{aws.String(vpcID)},
+	})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == "InvalidVpcID.NotFound" {
+			return nil, fmt.Errorf("%w: %q in region %s", ErrVPCNotFound, vpcID, region)
+		}
+		return nil, wrapAuthError(err)
+	}
+	if len(out.Vpcs) == 0 {
+		return nil, fmt.Errorf("%w: %q in region %s", ErrVPCNotFound, vpcID, region)
+	}
+	return out.Vpcs[0], nil
+}
+
+// AssertVPCExists fails the test unless the VPC exists and its state is
+// available.
+func AssertVPCExists(t *testing.T, region, vpcID string) {
+	t.Helper()
+
+	vpc, err := GetVPCE(region, vpcID)
+	if err != nil {
+		t.Fatalf("verifying VPC %q: %v", vpcID, err)
+	}
+	if state := aws.StringValue(vpc.State); state != ec2.VpcStateAvailable {
+		t.Fatalf("VPC %q has state %q, want %q", vpcID, state, ec2.VpcStateAvailable)
+	}
+}
+
+// AssertVPCGone polls until the VPC no longer exists, failing the test if
+// EC2 still reports it after vpcGoneTimeout.
+func AssertVPCGone(t *testing.T, region, vpcID string) {
+	t.Helper()
+
+	deadline := time.Now().Add(vpcGoneTimeout)
+	for {
+		vpc, err := GetVPCE(region, vpcID)
+		if errors.Is(err, ErrVPCNotFound) {
+			return
+		}
+		if err != nil {
+			t.Fatalf("checking VPC %q was deleted: %v", vpcID, err)
+		}
+
+		state := aws.StringValue(vpc.State)
+		if time.Now().Add(vpcGoneInterval).After(deadline) {
+			t.Fatalf("VPC %q still exists %s after destroy, state %q", vpcID, vpcGoneTimeout, state)
+		}
+		t.Logf("VPC %q still exists (%s), checking again in %s", vpcID, state, vpcGoneInterval)
+		time.Sleep(vpcGoneInterval)
+	}
+}
@@ -0,0 +1,84 @@
+package azureverify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// armModule identifies this package in the User-Agent of requests sent by
+// armRequest.
+const armModule = "terraform-tests/azureverify"
+
+// armRequest calls a Resource Manager operation that has no typed client in
+// the SDK modules we depend on, using azcore's pipeline for authentication,
+// retries and error decoding. path starts at /subscriptions/...; the response
+// body, if any, is decoded into out unless out is nil.
+func armRequest(ctx context.Context, subscriptionID, method, path, apiVersion string, query url.Values, out interface{}) (*http.Response, error) {
+	_, resp, err := armSend(ctx, subscriptionID, method, path, apiVersion, query)
+	if err != nil {
+		return resp, err
+	}
+	if out != nil && resp.StatusCode == http.StatusOK {
+		if err := runtime.UnmarshalAsJSON(resp, out); err != nil {
+			return resp, fmt.Errorf("decoding %s %s: %w", method, path, err)
+		}
+	}
+	return resp, nil
+}
+
+// armLongRunning calls a Resource Manager operation like armRequest and, if
+// it completes asynchronously, polls it every frequency until it finishes or
+// ctx is done.
+func armLongRunning(ctx context.Context, subscriptionID, method, path, apiVersion string, frequency time.Duration) error {
+	client, resp, err := armSend(ctx, subscriptionID, method, path, apiVersion, nil)
+	if err != nil {
+		return err
+	}
+	poller, err := runtime.NewPoller[struct{}](resp, client.Pipeline(), nil)
+	if err != nil {
+		return fmt.Errorf("polling %s %s: %w", method, path, err)
+	}
+	if _, err := poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: frequency}); err != nil {
+		return fmt.Errorf("waiting for %s %s: %w", method, path, wrapAuthError(err))
+	}
+	return nil
+}
+
+// armSend sends a Resource Manager request and checks it succeeded or was
+// accepted, returning the client so callers can keep using its pipeline.
+func armSend(ctx context.Context, subscriptionID, method, path, apiVersion string, query url.Values) (*arm.Client, *http.Response, error) {
+	cred, err := credentialFor(subscriptionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := arm.NewClient(armModule, "v1.0.0", cred, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating Resource Manager client: %w", err)
+	}
+
+	req, err := runtime.NewRequest(ctx, method, runtime.JoinPaths(client.Endpoint(), path))
+	if err != nil {
+		return nil, nil, fmt.Errorf("building %s %s: %w", method, path, err)
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api-version", apiVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+	req.Raw().Header.Set("Accept", "application/json")
+
+	resp, err := client.Pipeline().Do(req)
+	if err != nil {
+		return nil, nil, wrapAuthError(err)
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK, http.StatusAccepted, http.StatusNoContent) {
+		return client, resp, runtime.NewResponseError(resp)
+	}
+	return client, resp, nil
+}
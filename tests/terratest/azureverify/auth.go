@@ -0,0 +1,71 @@
+package azureverify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// managementScope is the token scope for Azure Resource Manager.
+const managementScope = "https://management.azure.com/.default"
+
+// authTimeout bounds the preflight token request, which can otherwise wait on
+// managed identity endpoints that do not exist outside Azure.
+const authTimeout = 30 * time.Second
+
+// CheckAuthE resolves the subscription under test and acquires a management
+// token to prove the configured credentials work. The subscription comes from
+// ARM_SUBSCRIPTION_ID or AZURE_SUBSCRIPTION_ID, falling back to the Azure CLI's
+// current account.
+func CheckAuthE() (string, error) {
+	subscriptionID := SubscriptionID()
+	if subscriptionID == "" {
+		cliSubscription, err := cliSubscriptionID()
+		if err != nil {
+			return "", fmt.Errorf("no ARM_SUBSCRIPTION_ID set and no Azure CLI login: %w", err)
+		}
+		subscriptionID = cliSubscription
+	}
+
+	cred, err := NewCredential()
+	if err != nil {
+		return "", wrapAuthError(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), authTimeout)
+	defer cancel()
+	if _, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{managementScope}}); err != nil {
+		return "", wrapAuthError(err)
+	}
+	return subscriptionID, nil
+}
+
+// RequireAzureAuth skips the test with an explicit message unless working
+// Azure credentials are available, and returns the subscription ID to pass
+// to the other helpers in this package.
+func RequireAzureAuth(t *testing.T) string {
+	t.Helper()
+
+	subscriptionID, err := CheckAuthE()
+	if err != nil {
+		t.Skipf("no usable Azure credentials: %v", err)
+	}
+	return subscriptionID
+}
+
+func cliSubscriptionID() (string, error) {
+	if _, err := exec.LookPath("az"); err != nil {
+		return "", errors.New("az is not installed")
+	}
+	out, err := exec.Command("az", "account", "show", "--query", "id", "--output", "tsv").Output()
+	if err != nil {
+		return "", fmt.Errorf("az account show: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,77 @@
+// Package azureverify queries Azure directly to confirm that the resources
+// Terraform reports as created actually exist in the expected state.
+package azureverify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+// SubscriptionID returns the subscription targeted by the azurerm provider,
+// preferring ARM_SUBSCRIPTION_ID over AZURE_SUBSCRIPTION_ID.
+func SubscriptionID() string {
+	return firstEnv("ARM_SUBSCRIPTION_ID", "AZURE_SUBSCRIPTION_ID")
+}
+
+// NewCredential builds an Azure credential from the ARM_* variables used by
+// the azurerm provider. When no client secret is configured it falls back to
+// DefaultAzureCredential, which covers the AZURE_* variables, workload
+// identity and an Azure CLI login such as the one made by azure/login.
+func NewCredential() (azcore.TokenCredential, error) {
+	tenantID := firstEnv("ARM_TENANT_ID", "AZURE_TENANT_ID")
+	clientID := firstEnv("ARM_CLIENT_ID", "AZURE_CLIENT_ID")
+	clientSecret := firstEnv("ARM_CLIENT_SECRET", "AZURE_CLIENT_SECRET")
+
+	if tenantID != "" && clientID != "" && clientSecret != "" {
+		cred, err := azidentity.NewClientSecretCredential(tenantID, clientID, clientSecret, nil)
+		if err != nil {
+			return nil, fmt.Errorf("creating client secret credential: %w", err)
+		}
+		return cred, nil
+	}
+
+	cred, err := azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+		TenantID: tenantID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating default Azure credential: %w", err)
+	}
+	return cred, nil
+}
+
+// credentialFor validates subscriptionID and returns a credential for
+// building management-plane clients against it.
+func credentialFor(subscriptionID string) (azcore.TokenCredential, error) {
+	if subscriptionID == "" {
+		return nil, errors.New("subscription ID is empty; set ARM_SUBSCRIPTION_ID or AZURE_SUBSCRIPTION_ID")
+	}
+	cred, err := NewCredential()
+	if err != nil {
+		return nil, wrapAuthError(err)
+	}
+	return cred, nil
+}
+
+// wrapAuthError turns credential failures into an error that points at the
+// environment configuration rather than at the resource being queried.
+func wrapAuthError(err error) error {
+	var authErr *azidentity.AuthenticationFailedError
+	var respErr *azcore.ResponseError
+	if errors.As(err, &authErr) || !errors.As(err, &respErr) {
+		return fmt.Errorf("authenticating to Azure (check ARM_*/AZURE_* credentials or az login): %w", err)
+	}
+	return err
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,18 @@
+package azureverify
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizeLocation reduces an Azure region display name such as "East US 2"
+// to its short name "eastus2", so either form can be compared or passed to
+// the management APIs.
+func NormalizeLocation(location string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, location)
+}
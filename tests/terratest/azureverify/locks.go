@@ -0,0 +1,70 @@
+package azureverify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// locksAPIVersion is the Microsoft.Authorization/locks API version queried.
+const locksAPIVersion = "2016-09-01"
+
+// ManagementLock is a lock that applies to a resource group, either placed on
+// it directly or inherited from the subscription.
+type ManagementLock struct {
+	Name  string
+	Level string
+	Notes string
+}
+
+// ListResourceGroupLocksE returns the management locks in effect at the
+// resource group's scope.
+func ListResourceGroupLocksE(subscriptionID, rgName string) ([]ManagementLock, error) {
+	var page struct {
+		Value []struct {
+			Name       string `json:"name"`
+			Properties struct {
+				Level string `json:"level"`
+				Notes string `json:"notes"`
+			} `json:"properties"`
+		} `json:"value"`
+	}
+	path := resourceGroupPath(subscriptionID, rgName) + "/providers/Microsoft.Authorization/locks"
+	query := url.Values{"$filter": {"atScope()"}}
+	if _, err := armRequest(context.Background(), subscriptionID, http.MethodGet, path, locksAPIVersion, query, &page); err != nil {
+		return nil, fmt.Errorf("listing locks on resource group %q: %w", rgName, err)
+	}
+
+	locks := make([]ManagementLock, 0, len(page.Value))
+	for _, lock := range page.Value {
+		locks = append(locks, ManagementLock{Name: lock.Name, Level: lock.Properties.Level, Notes: lock.Properties.Notes})
+	}
+	return locks, nil
+}
+
+// AssertResourceLock fails the test unless a lock of lockLevel, such as
+// CanNotDelete or ReadOnly, applies to the resource group.
+func AssertResourceLock(t *testing.T, subscriptionID, rgName, lockLevel string) {
+	t.Helper()
+
+	locks, err := ListResourceGroupLocksE(subscriptionID, rgName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	levels := make([]string, 0, len(locks))
+	for _, lock := range locks {
+		if strings.EqualFold(lock.Level, lockLevel) {
+			return
+		}
+		levels = append(levels, fmt.Sprintf("%s (%s)", lock.Name, lock.Level))
+	}
+	if len(levels) == 0 {
+		t.Errorf("resource group %q has no management locks, want %s", rgName, lockLevel)
+		return
+	}
+	t.Errorf("resource group %q has no %s lock; found %s", rgName, lockLevel, strings.Join(levels, ", "))
+}
@@ -0,0 +1,112 @@
+package azureverify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// policyInsightsAPIVersion is the Microsoft.PolicyInsights API version used.
+const policyInsightsAPIVersion = "2019-10-01"
+
+// A triggered scan of a resource group usually completes within 15 to 30
+// minutes; its operation is polled at policyPollInterval.
+const (
+	policyGracePeriod     = 30 * time.Minute
+	policyPollInterval    = 30 * time.Second
+	policyQueryMaxResults = "1000"
+)
+
+// PolicyState is the latest compliance of one resource against one policy
+// assignment.
+type PolicyState struct {
+	ResourceID           string `json:"resourceId"`
+	PolicyAssignmentName string `json:"policyAssignmentName"`
+	PolicyDefinitionName string `json:"policyDefinitionName"`
+	ComplianceState      string `json:"complianceState"`
+}
+
+// TriggerPolicyEvaluationE runs an on-demand compliance scan of the
+// resource group, waiting up to timeout for the scan's asynchronous
+// operation to complete so that the policy states read afterwards are
+// current.
+func TriggerPolicyEvaluationE(subscriptionID, rgName string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	path := resourceGroupPath(subscriptionID, rgName) + "/providers/Microsoft.PolicyInsights/policyStates/latest/triggerEvaluation"
+	if err := armLongRunning(ctx, subscriptionID, http.MethodPost, path, policyInsightsAPIVersion, policyPollInterval); err != nil {
+		return fmt.Errorf("evaluating policy compliance of resource group %q: %w", rgName, err)
+	}
+	return nil
+}
+
+// ListPolicyStatesE returns the latest policy states recorded for the
+// resource group and the resources in it.
+func ListPolicyStatesE(subscriptionID, rgName string) ([]PolicyState, error) {
+	var page struct {
+		Value []PolicyState `json:"value"`
+	}
+	path := resourceGroupPath(subscriptionID, rgName) + "/providers/Microsoft.PolicyInsights/policyStates/latest/queryResults"
+	query := url.Values{
+		"$select": {"resourceId,policyAssignmentName,policyDefinitionName,complianceState"},
+		"$top":    {policyQueryMaxResults},
+	}
+	if _, err := armRequest(context.Background(), subscriptionID, http.MethodPost, path, policyInsightsAPIVersion, query, &page); err != nil {
+		return nil, fmt.Errorf("querying policy states of resource group %q: %w", rgName, err)
+	}
+	return page.Value, nil
+}
+
+// AssertPolicyCompliant runs a compliance scan of the resource group, waiting
+// up to policyGracePeriod for it to complete, and fails the test listing
+// every non-compliant resource with the policy definitions it violates. If
+// the completed scan recorded no states, no policy assignment covers the
+// group and the check only logs.
+func AssertPolicyCompliant(t *testing.T, subscriptionID, rgName string) {
+	t.Helper()
+
+	t.Logf("evaluating policy compliance of resource group %q, this can take %s", rgName, policyGracePeriod)
+	if err := TriggerPolicyEvaluationE(subscriptionID, rgName, policyGracePeriod); err != nil {
+		t.Fatal(err)
+	}
+	states, err := ListPolicyStatesE(subscriptionID, rgName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) == 0 {
+		t.Logf("no policy states for resource group %q, assuming no policy applies", rgName)
+		return
+	}
+
+	violations := map[string][]string{}
+	for _, state := range states {
+		if strings.EqualFold(state.ComplianceState, "NonCompliant") {
+			violations[state.ResourceID] = append(violations[state.ResourceID], state.PolicyDefinitionName)
+		}
+	}
+	if len(violations) == 0 {
+		return
+	}
+
+	ids := make([]string, 0, len(violations))
+	for id := range violations {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var b strings.Builder
+	for _, id := range ids {
+		fmt.Fprintf(&b, "\n  %s: %s", id, strings.Join(violations[id], ", "))
+	}
+	t.Errorf("%d resource(s) in %q are not policy compliant:%s", len(ids), rgName, b.String())
+}
+
+func resourceGroupPath(subscriptionID, rgName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", url.PathEscape(subscriptionID), url.PathEscape(rgName))
+}
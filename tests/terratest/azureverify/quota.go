@@ -0,0 +1,95 @@
+package azureverify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+)
+
+// QuotaUsage is the current consumption of one compute quota in a region.
+type QuotaUsage struct {
+	Name    string
+	Current int64
+	Limit   int64
+}
+
+// Remaining returns how much of the quota is still free.
+func (u QuotaUsage) Remaining() int64 {
+	return u.Limit - u.Current
+}
+
+// GetComputeQuotaE returns compute usage in location keyed by quota name,
+// such as "cores" for total regional vCPUs.
+func GetComputeQuotaE(subscriptionID, location string) (map[string]QuotaUsage, error) {
+	cred, err := credentialFor(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := armcompute.NewUsageClient(subscriptionID, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating compute usage client: %w", err)
+	}
+
+	usages := map[string]QuotaUsage{}
+	pager := client.NewListPager(NormalizeLocation(location), nil)
+	for pager.More() {
+		page, err := pager.NextPage(context.Background())
+		if err != nil {
+			return nil, wrapAuthError(err)
+		}
+		for _, usage := range page.Value {
+			if usage.Name == nil || usage.Name.Value == nil || usage.CurrentValue == nil || usage.Limit == nil {
+				continue
+			}
+			usages[*usage.Name.Value] = QuotaUsage{
+				Name:    *usage.Name.Value,
+				Current: int64(*usage.CurrentValue),
+				Limit:   *usage.Limit,
+			}
+		}
+	}
+	return usages, nil
+}
+
+// AssertQuotaAvailable fails the test before anything is provisioned if any
+// quota in required, keyed by compute usage name, has less headroom in
+// location than requested. Each shortfall is reported with its size.
+func AssertQuotaAvailable(t *testing.T, subscriptionID, location string, required map[string]int) {
+	t.Helper()
+
+	if len(required) == 0 {
+		return
+	}
+
+	usages, err := GetComputeQuotaE(subscriptionID, location)
+	if err != nil {
+		t.Fatalf("reading compute quota in %s: %v", location, err)
+	}
+
+	names := make([]string, 0, len(required))
+	for name := range required {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var shortfalls []string
+	for _, name := range names {
+		want := int64(required[name])
+		usage, ok := usages[name]
+		if !ok {
+			shortfalls = append(shortfalls, fmt.Sprintf("%s: not reported in %s", name, location))
+			continue
+		}
+		if usage.Remaining() < want {
+			shortfalls = append(shortfalls, fmt.Sprintf("%s: short by %d (need %d, %d of %d used)",
+				name, want-usage.Remaining(), want, usage.Current, usage.Limit))
+		}
+	}
+	if len(shortfalls) > 0 {
+		t.Fatalf("insufficient quota in %s:\n  %s", location, strings.Join(shortfalls, "\n  "))
+	}
+}
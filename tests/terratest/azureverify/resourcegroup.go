@@ -0,0 +1,138 @@
+package azureverify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+// ErrResourceGroupNotFound is returned, wrapped, by GetResourceGroupE when
+// the resource group does not exist.
+var ErrResourceGroupNotFound = errors.New("resource group not found")
+
+// resourceGroupGoneTimeout bounds how long AssertResourceGroupGone waits for
+// a deletion that Terraform reported as complete to finish in Azure.
+const (
+	resourceGroupGoneTimeout  = 10 * time.Minute
+	resourceGroupGoneInterval = 15 * time.Second
+)
+
+// GetResourceGroupE fetches a resource group from Azure Resource Manager.
+func GetResourceGroupE(subscriptionID, rgName string) (*armresources.ResourceGroup, error) {
+	client, err := newResourceGroupsClient(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Get(context.Background(), rgName, nil)
+	if err != nil {
+		var respErr *azcore.ResponseError
+		if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %q in subscription %s", ErrResourceGroupNotFound, rgName, subscriptionID)
+		}
+		return nil, wrapAuthError(err)
+	}
+	return &resp.ResourceGroup, nil
+}
+
+// ListResourceGroupsE returns every resource group in the subscription.
+func ListResourceGroupsE(subscriptionID string) ([]*armresources.ResourceGroup, error) {
+	client, err := newResourceGroupsClient(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []*armresources.ResourceGroup
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(context.Background())
+		if err != nil {
+			return nil, wrapAuthError(err)
+		}
+		groups = append(groups, page.Value...)
+	}
+	return groups, nil
+}
+
+// DeleteResourceGroupE deletes a resource group and everything in it,
+// waiting for the deletion to finish.
+func DeleteResourceGroupE(subscriptionID, rgName string) error {
+	client, err := newResourceGroupsClient(subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	poller, err := client.BeginDelete(context.Background(), rgName, nil)
+	if err != nil {
+		return wrapAuthError(err)
+	}
+	if _, err := poller.PollUntilDone(context.Background(), nil); err != nil {
+		return fmt.Errorf("deleting resource group %q: %w", rgName, err)
+	}
+	return nil
+}
+
+// AssertResourceGroupExists fails the test unless the resource group exists
+// and its provisioning state is Succeeded.
+func AssertResourceGroupExists(t *testing.T, subscriptionID, rgName string) {
+	t.Helper()
+
+	rg, err := GetResourceGroupE(subscriptionID, rgName)
+	if err != nil {
+		t.Fatalf("verifying resource group %q: %v", rgName, err)
+	}
+
+	state := ""
+	if rg.Properties != nil && rg.Properties.ProvisioningState != nil {
+		state = *rg.Properties.ProvisioningState
+	}
+	if state != "Succeeded" {
+		t.Fatalf("resource group %q has provisioning state %q, want %q", rgName, state, "Succeeded")
+	}
+}
+
+// AssertResourceGroupGone polls until the resource group no longer exists,
+// failing the test if it is still there, typically in the Deleting state,
+// after resourceGroupGoneTimeout.
+func AssertResourceGroupGone(t *testing.T, subscriptionID, rgName string) {
+	t.Helper()
+
+	deadline := time.Now().Add(resourceGroupGoneTimeout)
+	for {
+		rg, err := GetResourceGroupE(subscriptionID, rgName)
+		if errors.Is(err, ErrResourceGroupNotFound) {
+			return
+		}
+		if err != nil {
+			t.Fatalf("checking resource group %q was deleted: %v", rgName, err)
+		}
+
+		state := ""
+		if rg.Properties != nil && rg.Properties.ProvisioningState != nil {
+			state = *rg.Properties.ProvisioningState
+		}
+		if time.Now().Add(resourceGroupGoneInterval).After(deadline) {
+			t.Fatalf("resource group %q still exists %s after destroy, provisioning state %q", rgName, resourceGroupGoneTimeout, state)
+		}
+		t.Logf("resource group %q still exists (%s), checking again in %s", rgName, state, resourceGroupGoneInterval)
+		time.Sleep(resourceGroupGoneInterval)
+	}
+}
+
+func newResourceGroupsClient(subscriptionID string) (*armresources.ResourceGroupsClient, error) {
+	cred, err := credentialFor(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := armresources.NewResourceGroupsClient(subscriptionID, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating resource groups client: %w", err)
+	}
+	return client, nil
+}
@@ -0,0 +1,90 @@
+package azureverify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+// MissingTagsE returns, keyed by resource ID, the required tag keys absent
+// from the resource group and from each resource inside it. Tag keys are
+// compared case-insensitively, as they are by Azure.
+func MissingTagsE(subscriptionID, rgName string, required []string) (map[string][]string, error) {
+	rg, err := GetResourceGroupE(subscriptionID, rgName)
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := credentialFor(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := armresources.NewClient(subscriptionID, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating resources client: %w", err)
+	}
+
+	missing := map[string][]string{}
+	if keys := missingKeys(rg.Tags, required); len(keys) > 0 {
+		missing[*rg.ID] = keys
+	}
+
+	pager := client.NewListByResourceGroupPager(rgName, nil)
+	for pager.More() {
+		page, err := pager.NextPage(context.Background())
+		if err != nil {
+			return nil, wrapAuthError(err)
+		}
+		for _, resource := range page.Value {
+			if keys := missingKeys(resource.Tags, required); len(keys) > 0 {
+				missing[*resource.ID] = keys
+			}
+		}
+	}
+	return missing, nil
+}
+
+// AssertRequiredTags fails the test listing every resource in the group,
+// including the group itself, that lacks one of the required tag keys.
+func AssertRequiredTags(t *testing.T, subscriptionID, rgName string, required []string) {
+	t.Helper()
+
+	missing, err := MissingTagsE(subscriptionID, rgName, required)
+	if err != nil {
+		t.Fatalf("checking tags in resource group %q: %v", rgName, err)
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	ids := make([]string, 0, len(missing))
+	for id := range missing {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var b strings.Builder
+	for _, id := range ids {
+		fmt.Fprintf(&b, "\n  %s: missing %s", id, strings.Join(missing[id], ", "))
+	}
+	t.Errorf("%d resource(s) in %q are missing required tags:%s", len(ids), rgName, b.String())
+}
+
+func missingKeys(tags map[string]*string, required []string) []string {
+	present := make(map[string]bool, len(tags))
+	for key := range tags {
+		present[strings.ToLower(key)] = true
+	}
+
+	var missing []string
+	for _, key := range required {
+		if !present[strings.ToLower(key)] {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,266 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// backendEnvOverrides maps environment variables to the azurerm backend
+// settings they override.
+var backendEnvOverrides = map[string]string{
+	"TF_BACKEND_RESOURCE_GROUP":  "resource_group_name",
+	"TF_BACKEND_STORAGE_ACCOUNT": "storage_account_name",
+	"TF_BACKEND_CONTAINER":       "container_name",
+}
+
+// backendLocationKeys must all be set for the backend config to be used.
+var backendLocationKeys = []string{"resource_group_name", "storage_account_name", "container_name"}
+
+// localBackendOverrideFile is written into a test's copy of an environment
+// by withLocalBackend.
+const localBackendOverrideFile = "terratest_backend_override.tf"
+
+// localBackendOverride replaces the environment's remote backend, which
+// Terraform allows in *_override.tf files.
+const localBackendOverride = `terraform {
+  backend "local" {}
+}
+`
+
+// withLocalBackend switches opts.TerraformDir, which must be a copy from
+// copyEnvironment, to local state. Plan-only tests use it to plan from empty
+// state without TF_BACKEND_* settings or the shared state lock, and
+// withBackendConfig falls back to it when those settings are incomplete.
+func withLocalBackend(t *testing.T, opts *terraform.Options) *terraform.Options {
+	t.Helper()
+
+	path := filepath.Join(opts.TerraformDir, localBackendOverrideFile)
+	if err := os.WriteFile(path, []byte(localBackendOverride), 0o644); err != nil {
+		t.Fatalf("writing backend override: %v", err)
+	}
+	return opts
+}
+
+// withBackendConfig points opts at the Azure Storage backend assembled by
+// mergeBackendConfig from opts.BackendConfig, the shared backend.hcl named by
+// TF_BACKEND_CONFIG_FILE and the TF_BACKEND_* variables, storing state under
+// key. If the result does not name a resource group, storage account and
+// container, opts.TerraformDir, which must be a copy from copyEnvironment,
+// is switched to local state instead, so local runs still work.
+func withBackendConfig(t *testing.T, opts *terraform.Options, key string) {
+	t.Helper()
+
+	// The shared config is assembled once per run; only options that bring
+	// their own settings need merging again.
+	config := map[string]interface{}{}
+	if len(opts.BackendConfig) == 0 {
+		for key, value := range requireSuite(t).backend {
+			config[key] = value
+		}
+	} else {
+		var err error
+		if config, err = mergeBackendConfig(opts.BackendConfig, os.Getenv("TF_BACKEND_CONFIG_FILE")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, required := range backendLocationKeys {
+		if value, ok := config[required]; !ok || value == "" {
+			t.Logf("backend config has no %s, using local state", required)
+			withLocalBackend(t, opts)
+			return
+		}
+	}
+
+	// Each test's key is deliberate, so it replaces any key in the shared
+	// config without counting as a conflict.
+	config["key"] = key
+	opts.BackendConfig = config
+
+	// The state key changes between tests sharing a directory.
+	opts.Reconfigure = true
+}
+
+// mergeBackendConfig returns base overlaid with the attributes of the HCL
+// file at hclPath, if any, and then with the TF_BACKEND_* variables in
+// backendEnvOverrides. base is not modified. With TF_TEST_BACKEND_STRICT=true,
+// a key given different values by two sources is an error rather than being
+// silently overridden.
+func mergeBackendConfig(base map[string]interface{}, hclPath string) (map[string]interface{}, error) {
+	merged := make(map[string]interface{}, len(base))
+	sources := make(map[string]string, len(base))
+	for key, value := range base {
+		merged[key] = value
+		sources[key] = "options"
+	}
+
+	var conflicts []string
+	overlay := func(key string, value interface{}, source string) {
+		if previous, ok := merged[key]; ok && fmt.Sprint(previous) != fmt.Sprint(value) {
+			conflicts = append(conflicts, fmt.Sprintf("%s: %v from %s, %v from %s", key, previous, sources[key], value, source))
+		}
+		merged[key] = value
+		sources[key] = source
+	}
+
+	if hclPath != "" {
+		fileConfig, err := loadBackendHCL(hclPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range sortedKeys(fileConfig) {
+			overlay(key, fileConfig[key], hclPath)
+		}
+	}
+
+	envNames := make([]string, 0, len(backendEnvOverrides))
+	for name := range backendEnvOverrides {
+		envNames = append(envNames, name)
+	}
+	sort.Strings(envNames)
+	for _, name := range envNames {
+		if value := os.Getenv(name); value != "" {
+			overlay(backendEnvOverrides[name], value, name)
+		}
+	}
+
+	if len(conflicts) > 0 && envFlag("TF_TEST_BACKEND_STRICT") {
+		return nil, fmt.Errorf("conflicting backend config:\n  %s", strings.Join(conflicts, "\n  "))
+	}
+	return merged, nil
+}
+
+// loadBackendHCL reads a partial backend configuration file of the form
+// passed to `terraform init -backend-config`: top-level attributes with
+// string, number or bool values.
+func loadBackendHCL(path string) (map[string]interface{}, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading backend config: %w", err)
+	}
+	file, diags := hclparse.NewParser().ParseHCL(src, path)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("parsing backend config %s: %s", path, diags.Error())
+	}
+	attrs, diags := file.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("reading backend config %s: %s", path, diags.Error())
+	}
+
+	config := make(map[string]interface{}, len(attrs))
+	for name, attr := range attrs {
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("evaluating %s in %s: %s", name, path, diags.Error())
+		}
+		goValue, err := ctyPrimitive(value)
+		if err != nil {
+			return nil, fmt.Errorf("backend config %s in %s: %w", name, path, err)
+		}
+		config[name] = goValue
+	}
+	return config, nil
+}
+
+// ctyPrimitive converts a known string, number or bool to its Go value.
+func ctyPrimitive(value cty.Value) (interface{}, error) {
+	if value.IsNull() || !value.IsKnown() {
+		return nil, fmt.Errorf("value must be known and not null")
+	}
+	switch value.Type() {
+	case cty.String:
+		return value.AsString(), nil
+	case cty.Bool:
+		return value.True(), nil
+	case cty.Number:
+		if i, accuracy := value.AsBigFloat().Int64(); accuracy == 0 {
+			return i, nil
+		}
+		f, _ := value.AsBigFloat().Float64()
+		return f, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", value.Type().FriendlyName())
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestMergeBackendConfig(t *testing.T) {
+	hclPath := filepath.Join(t.TempDir(), "backend.hcl")
+	hcl := `resource_group_name  = "file-rg"
+storage_account_name = "filesa"
+use_azuread_auth     = true
+`
+	if err := os.WriteFile(hclPath, []byte(hcl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	base := map[string]interface{}{"resource_group_name": "options-rg", "container_name": "options-container"}
+
+	t.Run("precedence", func(t *testing.T) {
+		t.Setenv("TF_TEST_BACKEND_STRICT", "")
+		t.Setenv("TF_BACKEND_RESOURCE_GROUP", "")
+		t.Setenv("TF_BACKEND_STORAGE_ACCOUNT", "envsa")
+		t.Setenv("TF_BACKEND_CONTAINER", "")
+
+		got, err := mergeBackendConfig(base, hclPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]interface{}{
+			"resource_group_name":  "file-rg",
+			"storage_account_name": "envsa",
+			"container_name":       "options-container",
+			"use_azuread_auth":     true,
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if base["resource_group_name"] != "options-rg" {
+			t.Errorf("base was modified: %v", base)
+		}
+	})
+
+	t.Run("strict conflict", func(t *testing.T) {
+		t.Setenv("TF_TEST_BACKEND_STRICT", "true")
+		t.Setenv("TF_BACKEND_RESOURCE_GROUP", "")
+		t.Setenv("TF_BACKEND_STORAGE_ACCOUNT", "envsa")
+		t.Setenv("TF_BACKEND_CONTAINER", "")
+
+		_, err := mergeBackendConfig(base, hclPath)
+		if err == nil {
+			t.Fatal("conflicting values were merged")
+		}
+		for _, want := range []string{
+			"resource_group_name: options-rg from options, file-rg from " + hclPath,
+			"storage_account_name: filesa from " + hclPath + ", envsa from TF_BACKEND_STORAGE_ACCOUNT",
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("error %q does not report %q", err, want)
+			}
+		}
+	})
+
+	t.Run("strict agreement", func(t *testing.T) {
+		t.Setenv("TF_TEST_BACKEND_STRICT", "true")
+		t.Setenv("TF_BACKEND_RESOURCE_GROUP", "file-rg")
+		t.Setenv("TF_BACKEND_STORAGE_ACCOUNT", "")
+		t.Setenv("TF_BACKEND_CONTAINER", "")
+
+		if _, err := mergeBackendConfig(map[string]interface{}{"container_name": "options-container"}, hclPath); err != nil {
+			t.Errorf("equal values from two sources were rejected: %v", err)
+		}
+	})
+}
@@ -1,25 +1,154 @@
 package test
 
 import (
+	"os"
 	"testing"
+	"time"
 
 	"github.com/gruntwork-io/terratest/modules/terraform"
-	"github.com/stretchr/testify/assert"
+	terratesting "github.com/gruntwork-io/terratest/modules/testing"
+
+	"terraform-tests/steps"
 )
 
+// requiredTags are the tag keys our tagging policy mandates on every resource.
+var requiredTags = []string{"environment", "owner", "cost-center"}
+
+// requiredQuota is the compute quota, by usage name such as "cores", that
+// must be free before applying. The example module only creates a resource
+// group, so it needs none yet.
+var requiredQuota = map[string]int{}
+
+// basicTerraformOptions returns the options shared by the apply-based tests
+// against the environment under test in the verifier's cloud, built by
+// environmentOptions with a fresh name for the primary resource.
+func basicTerraformOptions(t *testing.T, verifier cloudVerifier) *terraform.Options {
+	name, vars := basicVars(verifier)
+	return withPinnedProvider(t, environmentOptions(t, verifier, cloudEnvironment(testEnvironment()), name, vars))
+}
+
+// planTerraformOptions is basicTerraformOptions for tests that only plan.
+// It uses localOptions instead, so the plan starts from empty state, needs
+// no backend settings and takes no lock that concurrent runs would share.
+func planTerraformOptions(t *testing.T, verifier cloudVerifier) *terraform.Options {
+	_, vars := basicVars(verifier)
+	return withPinnedProvider(t, localOptions(t, verifier, cloudEnvironment(testEnvironment()), vars))
+}
+
+// basicVars returns a fresh name for the primary resource and the variables
+// that create it with the tags our policies require.
+func basicVars(verifier cloudVerifier) (string, map[string]interface{}) {
+	name := verifier.NewName()
+
+	vars := verifier.Vars(name)
+	vars["tags"] = map[string]string{
+		"ManagedBy":   "terraform",
+		"owner":       "terratest",
+		"cost-center": "terratest",
+		createdTag:    time.Now().UTC().Format(time.RFC3339),
+	}
+	return name, vars
+}
+
+// withPinnedProvider pins the cloud provider to TF_TEST_PROVIDER_VERSION,
+// if it is set.
+func withPinnedProvider(t *testing.T, opts *terraform.Options) *terraform.Options {
+	if version := os.Getenv("TF_TEST_PROVIDER_VERSION"); version != "" {
+		withProviderVersion(t, opts, version)
+	}
+	return opts
+}
+
+// stateLockTimeout is how long a command waits for another to release the
+// state lock, such as a destroy started while an interrupted apply is still
+// stopping.
+const stateLockTimeout = "5m"
+
+// withStateLock returns a copy of opts that takes the state lock, waiting up
+// to stateLockTimeout unless opts sets its own timeout. terratest passes
+// -lock=false unless Lock is set.
+func withStateLock(opts *terraform.Options) *terraform.Options {
+	locked := *opts
+	locked.Lock = true
+	if locked.LockTimeout == "" {
+		locked.LockTimeout = stateLockTimeout
+	}
+	return &locked
+}
+
+// environmentOptions returns options for applying vars to env, in the
+// verifier's cloud, from its own copy of the Terraform directory, in a
+// workspace called name under the environment's backend state key. Pass a
+// per-test name, such as one from uniqueName, so concurrent runs and
+// parallel tests can share the key. Every command takes the state lock, so
+// a destroy started by an interrupt or timeout waits for the apply.
+func environmentOptions(t *testing.T, verifier cloudVerifier, env, name string, vars map[string]interface{}) *terraform.Options {
+	terraformOptions := verifier.WithRetries(terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: copyEnvironment(t, env),
+		Vars:         vars,
+		Lock:         true,
+		LockTimeout:  stateLockTimeout,
+	}))
+	verifier.WithBackend(t, terraformOptions, "terratest/"+env+".tfstate")
+	return withWorkspace(terraformOptions, name)
+}
+
+// localOptions returns options for planning vars against env, in the
+// verifier's cloud, from its own copy of the Terraform directory, with local
+// state; see withLocalBackend.
+func localOptions(t *testing.T, verifier cloudVerifier, env string, vars map[string]interface{}) *terraform.Options {
+	terraformOptions := verifier.WithRetries(terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: copyEnvironment(t, env),
+		Vars:         vars,
+	}))
+	return withLocalBackend(t, terraformOptions)
+}
+
 func TestTerraformBasicExample(t *testing.T) {
-	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
-		TerraformDir: "../../terraform/environments/staging",
-		Vars: map[string]interface{}{
-			"resource_group_name": "test-rg-terratest",
-			"location":            "East US",
-		},
+	requireApply(t)
+	verifier := requireCloudAuth(t)
+	t.Parallel()
+
+	terraformOptions := basicTerraformOptions(t, verifier)
+
+	steps.Step(t, "preflight", func() {
+		verifier.Preflight(t, terraformOptions)
+	})
+
+	// Deferred first so it runs after cleanupOnExit's destroy. The check is
+	// taken once the apply succeeds, while the resources still exist.
+	verifyDestroy := envFlag("TF_TEST_VERIFY_DESTROY") && !envFlag("TF_TEST_SKIP_DESTROY")
+	var assertDestroyed func(t *testing.T)
+	defer func() {
+		if assertDestroyed != nil {
+			steps.Step(t, "verify-destroy", func() {
+				assertDestroyed(t)
+			})
+		}
+	}()
+	defer cleanupOnExit(t, terraformOptions)
+	trackForInterrupt(t, terraformOptions)
+	steps.Step(t, "init", func() {
+		initWorkspace(t, terraformOptions)
+	})
+	steps.Step(t, "apply", func() {
+		WithTimeout(t, 0, func(t terratesting.TestingT) {
+			terraform.Apply(t, terraformOptions)
+		}, terraformOptions)
+		if verifyDestroy {
+			assertDestroyed = verifier.DestroyCheck(t, terraformOptions)
+		}
 	})
 
-	// defer terraform.Destroy(t, terraformOptions)
-	terraform.InitAndApply(t, terraformOptions)
+	if path := os.Getenv("TF_TEST_INVENTORY_PATH"); path != "" {
+		steps.Step(t, "inventory", func() {
+			WriteInventory(t, terraformOptions, path)
+		})
+	}
 
-	// Validate outputs
-	resourceGroupName := terraform.Output(t, terraformOptions, "resource_group_name")
-	assert.Equal(t, "test-rg-terratest", resourceGroupName)
+	steps.Step(t, "validate", func() {
+		verifier.AssertDeployed(t, terraformOptions)
+		assertServiceEndpoint(t, terraformOptions)
+		assertSubnetIDs(t, terraformOptions)
+	})
 }
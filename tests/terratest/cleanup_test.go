@@ -0,0 +1,44 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+
+	"terraform-tests/steps"
+)
+
+// cleanupOnExit destroys everything in opts and must be deferred directly so
+// that it can recover a panic in the test body, tear down, and re-panic.
+//
+// Set TF_TEST_SKIP_DESTROY=true to keep the resources around for debugging;
+// remember to destroy them by hand afterwards.
+func cleanupOnExit(t *testing.T, opts *terraform.Options) {
+	r := recover()
+
+	steps.Step(t, "destroy", func() {
+		destroyUnlessSkipped(t, opts)
+	})
+
+	if r != nil {
+		panic(r)
+	}
+}
+
+// destroyUnlessSkipped runs terraform destroy unless TF_TEST_SKIP_DESTROY is
+// set, then deletes the workspace from withWorkspace, if any. After an interrupt it leaves the
+// destroy to handleInterrupts and waits for it instead.
+func destroyUnlessSkipped(t *testing.T, opts *terraform.Options) {
+	if envFlag("TF_TEST_SKIP_DESTROY") {
+		t.Logf("TF_TEST_SKIP_DESTROY is set, leaving resources in %s", opts.TerraformDir)
+		return
+	}
+	if interruptReceived() {
+		waitForInterruptCleanup(t, opts)
+		return
+	}
+	// Destroy stops the test on failure, leaving opts tracked.
+	terraform.Destroy(t, opts)
+	untrackForInterrupt(opts)
+	deleteWorkspace(t, opts)
+}
@@ -0,0 +1,199 @@
+package test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+
+	"terraform-tests/awsverify"
+	"terraform-tests/azureverify"
+)
+
+// Clouds selectable with TF_TEST_CLOUD.
+const (
+	cloudAzure = "azure"
+	cloudAWS   = "aws"
+)
+
+// awsEnvironmentPrefix marks environment directories that deploy to AWS,
+// such as aws-staging. Every other environment deploys to Azure.
+const awsEnvironmentPrefix = "aws-"
+
+// testCloud returns the cloud under test, read from TF_TEST_CLOUD and
+// defaulting to azure.
+func testCloud() string {
+	if cloud := os.Getenv("TF_TEST_CLOUD"); cloud != "" {
+		return strings.ToLower(cloud)
+	}
+	return cloudAzure
+}
+
+// cloudOf returns the cloud the environment directory env deploys to.
+func cloudOf(env string) string {
+	if strings.HasPrefix(env, awsEnvironmentPrefix) {
+		return cloudAWS
+	}
+	return cloudAzure
+}
+
+// cloudEnvironment returns the directory name of env in the cloud under
+// test, so TF_TEST_ENV=staging selects aws-staging when TF_TEST_CLOUD=aws.
+func cloudEnvironment(env string) string {
+	if testCloud() == cloudAWS {
+		return awsEnvironmentPrefix + env
+	}
+	return env
+}
+
+// requireCloud skips tests written against a single cloud when another one
+// is under test.
+func requireCloud(t *testing.T, cloud string) {
+	t.Helper()
+
+	if testCloud() != cloud {
+		t.Skipf("%s-only test, TF_TEST_CLOUD is %s", cloud, testCloud())
+	}
+}
+
+// cloudVerifier holds what differs between clouds in the apply-based tests:
+// the variables naming the primary resource, how to configure Terraform for
+// the cloud, and how to check the resource exists.
+type cloudVerifier interface {
+	// NewName returns a fresh name for the environment's primary resource.
+	NewName() string
+	// Vars returns the input variables that name and place the primary
+	// resource.
+	Vars(name string) map[string]interface{}
+	// WithRetries adds the cloud's transient API errors to the errors
+	// terratest retries.
+	WithRetries(opts *terraform.Options) *terraform.Options
+	// WithBackend points opts at the cloud's remote state, storing it under
+	// key.
+	WithBackend(t *testing.T, opts *terraform.Options, key string)
+	// Preflight checks the account has room for the deployment.
+	Preflight(t *testing.T, opts *terraform.Options)
+	// AssertDeployed checks the applied primary resource exists in the cloud,
+	// follows our conventions and carries the required tags.
+	AssertDeployed(t *testing.T, opts *terraform.Options)
+	// DestroyCheck returns a check, to run after terraform destroy, that
+	// the primary resource is gone from the cloud. Call it after applying,
+	// while the outputs identifying the resource are still in state.
+	DestroyCheck(t *testing.T, opts *terraform.Options) func(t *testing.T)
+}
+
+// productionLockLevel is the management lock production resource groups
+// must carry.
+const productionLockLevel = "CanNotDelete"
+
+// azureVerifier checks environments whose primary resource is a resource
+// group, named by the resource_group_name variable.
+type azureVerifier struct {
+	subscriptionID string
+}
+
+func (azureVerifier) NewName() string {
+	return uniqueName(testEnvironment() + "-rg-terratest")
+}
+
+func (azureVerifier) Vars(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"resource_group_name": name,
+		"location":            "East US",
+	}
+}
+
+func (azureVerifier) WithRetries(opts *terraform.Options) *terraform.Options {
+	return withAzureRetryableErrors(opts)
+}
+
+func (azureVerifier) WithBackend(t *testing.T, opts *terraform.Options, key string) {
+	withBackendConfig(t, opts, key)
+}
+
+func (v azureVerifier) Preflight(t *testing.T, opts *terraform.Options) {
+	azureverify.AssertQuotaAvailable(t, v.subscriptionID, opts.Vars["location"].(string), requiredQuota)
+}
+
+func (v azureVerifier) AssertDeployed(t *testing.T, opts *terraform.Options) {
+	t.Helper()
+
+	resourceGroupName := terraform.Output(t, opts, "resource_group_name")
+	assert.Equal(t, opts.Vars["resource_group_name"], resourceGroupName)
+	AssertNamingConvention(t, resourceGroupName, resourceGroupNamePattern)
+	AssertAllowedLocation(t, terraform.Output(t, opts, "location"), allowedLocations)
+	AssertResourceCount(t, opts, "azurerm_resource_group", 1)
+
+	// Confirm the resource group exists in Azure, not just in state
+	azureverify.AssertResourceGroupExists(t, v.subscriptionID, resourceGroupName)
+	azureverify.AssertRequiredTags(t, v.subscriptionID, resourceGroupName, requiredTags)
+
+	// Compliance scans take many minutes, so they are opt-in.
+	if envFlag("TF_TEST_POLICY_CHECK") {
+		azureverify.AssertPolicyCompliant(t, v.subscriptionID, resourceGroupName)
+	}
+
+	// Production resource groups must be protected from accidental deletion.
+	if isProduction(testEnvironment()) {
+		azureverify.AssertResourceLock(t, v.subscriptionID, resourceGroupName, productionLockLevel)
+	}
+}
+
+func (v azureVerifier) DestroyCheck(t *testing.T, opts *terraform.Options) func(t *testing.T) {
+	resourceGroupName := opts.Vars["resource_group_name"].(string)
+	return func(t *testing.T) {
+		azureverify.AssertResourceGroupGone(t, v.subscriptionID, resourceGroupName)
+	}
+}
+
+// awsVerifier checks environments whose primary resource is a VPC, named by
+// the name variable and reported by the vpc_id output.
+type awsVerifier struct {
+	region string
+}
+
+func (awsVerifier) NewName() string {
+	return uniqueName(testEnvironment() + "-vpc-terratest")
+}
+
+func (v awsVerifier) Vars(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":   name,
+		"region": v.region,
+	}
+}
+
+func (awsVerifier) WithRetries(opts *terraform.Options) *terraform.Options {
+	return opts
+}
+
+// WithBackend leaves opts unchanged: the AWS environments have no remote
+// backend yet, so their state stays in the test's copy of the directory.
+func (awsVerifier) WithBackend(t *testing.T, opts *terraform.Options, key string) {}
+
+func (awsVerifier) Preflight(t *testing.T, opts *terraform.Options) {}
+
+func (v awsVerifier) AssertDeployed(t *testing.T, opts *terraform.Options) {
+	t.Helper()
+
+	AssertNamingConvention(t, opts.Vars["name"].(string), resourceGroupNamePattern)
+	AssertResourceCount(t, opts, "aws_vpc", 1)
+
+	// Confirm the VPC exists in AWS, not just in state
+	vpcID := terraform.Output(t, opts, "vpc_id")
+	awsverify.AssertVPCExists(t, v.region, vpcID)
+	awsverify.AssertRequiredTags(t, v.region, vpcID, requiredTags)
+}
+
+// DestroyCheck reads the VPC ID now, since the vpc_id output is gone once
+// destroy has run.
+func (v awsVerifier) DestroyCheck(t *testing.T, opts *terraform.Options) func(t *testing.T) {
+	t.Helper()
+
+	vpcID := terraform.Output(t, opts, "vpc_id")
+	return func(t *testing.T) {
+		awsverify.AssertVPCGone(t, v.region, vpcID)
+	}
+}
@@ -0,0 +1,180 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/shell"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"gopkg.in/yaml.v3"
+)
+
+// budgetsFile holds the per-environment limits used by TestCostBudget.
+const budgetsFile = "budgets.yaml"
+
+// topCostDriverCount is how many of the most expensive resources are listed
+// when an environment is over budget.
+const topCostDriverCount = 5
+
+// Budgets are the monthly cost ceilings, in USD, for each environment.
+type Budgets struct {
+	MonthlyUSD map[string]float64 `yaml:"monthly_usd"`
+}
+
+// LoadBudgets reads Budgets from a YAML file.
+func LoadBudgets(path string) (Budgets, error) {
+	var budgets Budgets
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return budgets, fmt.Errorf("reading budgets: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &budgets); err != nil {
+		return budgets, fmt.Errorf("parsing budgets %s: %w", path, err)
+	}
+	return budgets, nil
+}
+
+// infracostReport is the subset of `infracost breakdown --format json`
+// output used by the cost tests.
+type infracostReport struct {
+	TotalMonthlyCost *string `json:"totalMonthlyCost"`
+	Projects         []struct {
+		Breakdown struct {
+			Resources []struct {
+				Name        string  `json:"name"`
+				MonthlyCost *string `json:"monthlyCost"`
+			} `json:"resources"`
+		} `json:"breakdown"`
+	} `json:"projects"`
+}
+
+// monthlyTotal returns the total monthly cost in USD, treating a missing
+// total as zero.
+func (r infracostReport) monthlyTotal(t *testing.T) float64 {
+	t.Helper()
+	return parseCost(t, r.TotalMonthlyCost)
+}
+
+// costDriver is one resource's share of the monthly cost.
+type costDriver struct {
+	Name        string
+	MonthlyCost float64
+}
+
+// topCostDrivers returns up to n resources across all projects, most
+// expensive first.
+func (r infracostReport) topCostDrivers(t *testing.T, n int) []costDriver {
+	t.Helper()
+
+	var drivers []costDriver
+	for _, project := range r.Projects {
+		for _, resource := range project.Breakdown.Resources {
+			drivers = append(drivers, costDriver{Name: resource.Name, MonthlyCost: parseCost(t, resource.MonthlyCost)})
+		}
+	}
+	sort.SliceStable(drivers, func(i, j int) bool { return drivers[i].MonthlyCost > drivers[j].MonthlyCost })
+	if len(drivers) > n {
+		drivers = drivers[:n]
+	}
+	return drivers
+}
+
+func parseCost(t *testing.T, cost *string) float64 {
+	t.Helper()
+
+	if cost == nil || *cost == "" {
+		return 0
+	}
+	value, err := strconv.ParseFloat(*cost, 64)
+	if err != nil {
+		t.Fatalf("parsing infracost cost %q: %v", *cost, err)
+	}
+	return value
+}
+
+// infracostBreakdown plans opts to a temporary plan file and runs infracost
+// over its JSON form. The plan files are removed when the test ends.
+func infracostBreakdown(t *testing.T, opts *terraform.Options) infracostReport {
+	t.Helper()
+
+	planDir := t.TempDir()
+	opts.PlanFilePath = filepath.Join(planDir, "tfplan")
+	planJSON := terraform.InitAndPlanAndShow(t, opts)
+
+	planJSONPath := filepath.Join(planDir, "tfplan.json")
+	if err := os.WriteFile(planJSONPath, []byte(planJSON), 0o600); err != nil {
+		t.Fatalf("writing plan JSON: %v", err)
+	}
+
+	output := shell.RunCommandAndGetStdOut(t, shell.Command{
+		Command: "infracost",
+		Args:    []string{"breakdown", "--path", planJSONPath, "--format", "json"},
+	})
+
+	var report infracostReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("parsing infracost output: %v", err)
+	}
+	return report
+}
+
+// TestCostEstimate fails when the estimated monthly cost of the environment
+// exceeds TF_TEST_MAX_MONTHLY_USD.
+func TestCostEstimate(t *testing.T) {
+	requireTool(t, "infracost", "https://www.infracost.io/docs/")
+
+	rawLimit := os.Getenv("TF_TEST_MAX_MONTHLY_USD")
+	if rawLimit == "" {
+		t.Skip("TF_TEST_MAX_MONTHLY_USD is not set")
+	}
+	limit, err := strconv.ParseFloat(rawLimit, 64)
+	if err != nil {
+		t.Fatalf("parsing TF_TEST_MAX_MONTHLY_USD %q: %v", rawLimit, err)
+	}
+
+	verifier := requireCloudAuth(t)
+	report := infracostBreakdown(t, planTerraformOptions(t, verifier))
+
+	total := report.monthlyTotal(t)
+	t.Logf("estimated monthly cost: $%.2f (limit $%.2f)", total, limit)
+	if total > limit {
+		t.Fatalf("estimated monthly cost $%.2f exceeds limit $%.2f", total, limit)
+	}
+}
+
+// TestCostBudget fails when the estimated monthly cost of the environment
+// exceeds its limit in budgets.yaml, listing the resources that cost the most.
+func TestCostBudget(t *testing.T) {
+	requireTool(t, "infracost", "https://www.infracost.io/docs/")
+
+	budgets, err := LoadBudgets(budgetsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	limit, ok := budgets.MonthlyUSD[testEnvironment()]
+	if !ok {
+		t.Fatalf("no budget for environment %q in %s", testEnvironment(), budgetsFile)
+	}
+
+	verifier := requireCloudAuth(t)
+	report := infracostBreakdown(t, planTerraformOptions(t, verifier))
+
+	total := report.monthlyTotal(t)
+	t.Logf("estimated monthly cost of %s: $%.2f (budget $%.2f)", testEnvironment(), total, limit)
+	if total <= limit {
+		return
+	}
+
+	var b strings.Builder
+	for _, driver := range report.topCostDrivers(t, topCostDriverCount) {
+		fmt.Fprintf(&b, "\n  $%.2f  %s", driver.MonthlyCost, driver.Name)
+	}
+	t.Fatalf("estimated monthly cost $%.2f exceeds the %s budget of $%.2f; top cost drivers:%s", total, testEnvironment(), limit, b.String())
+}
@@ -0,0 +1,34 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// TestDetectDrift plans the deployed environment against its real state and
+// fails if the live infrastructure no longer matches the configuration. It
+// only runs init and plan, so nothing is modified. Set
+// TF_TEST_DRIFT_CHECK=true to enable it.
+func TestDetectDrift(t *testing.T) {
+	if !envFlag("TF_TEST_DRIFT_CHECK") {
+		t.Skip("set TF_TEST_DRIFT_CHECK=true to check deployed infrastructure for drift")
+	}
+	requireAzureAuth(t)
+
+	// No Vars: the deployed configuration uses the environment's defaults.
+	// Init runs in a copy, keeping .terraform out of the checked-in tree.
+	terraformOptions := withAzureRetryableErrors(terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: copyEnvironment(t, testEnvironment()),
+	}))
+	withBackendConfig(t, terraformOptions, testEnvironment()+".tfstate")
+
+	terraform.Init(t, terraformOptions)
+	switch exitCode := terraform.PlanExitCode(t, terraformOptions); exitCode {
+	case 0:
+	case 2:
+		t.Fatalf("drift detected in %s:\n%s", testEnvironment(), terraform.Plan(t, terraformOptions))
+	default:
+		t.Fatalf("drift plan exited with code %d", exitCode)
+	}
+}
@@ -0,0 +1,58 @@
+package test
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	http_helper "github.com/gruntwork-io/terratest/modules/http-helper"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+const (
+	endpointInitialBackoff = 5 * time.Second
+	endpointMaxBackoff     = 60 * time.Second
+)
+
+// AssertEndpointHealthy polls url until it returns expectedStatus, making up
+// to retries attempts with exponential backoff to ride out DNS propagation
+// and slow starts. On timeout it fails with the last status and body seen.
+func AssertEndpointHealthy(t *testing.T, url string, expectedStatus int, retries int) {
+	t.Helper()
+
+	var (
+		status  int
+		body    string
+		err     error
+		backoff = endpointInitialBackoff
+	)
+	for attempt := 1; attempt <= retries; attempt++ {
+		status, body, err = http_helper.HttpGetE(t, url, &tls.Config{})
+		if err == nil && status == expectedStatus {
+			return
+		}
+		if attempt == retries {
+			break
+		}
+
+		t.Logf("%s not healthy yet (attempt %d/%d, status %d, err %v); retrying in %s", url, attempt, retries, status, err, backoff)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > endpointMaxBackoff {
+			backoff = endpointMaxBackoff
+		}
+	}
+	t.Fatalf("%s did not return %d after %d attempts; last status %d, err %v, body:\n%s", url, expectedStatus, retries, status, err, body)
+}
+
+// assertServiceEndpoint checks the service_url output when the environment
+// exposes one.
+func assertServiceEndpoint(t *testing.T, opts *terraform.Options) {
+	t.Helper()
+
+	url, err := terraform.OutputE(t, opts, "service_url")
+	if err != nil || url == "" {
+		t.Log("no service_url output, skipping endpoint check")
+		return
+	}
+	AssertEndpointHealthy(t, url, 200, 10)
+}
@@ -0,0 +1,144 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// environmentsRoot is the directory holding one Terraform root module per
+// environment, relative to this package.
+const environmentsRoot = "../../terraform/environments"
+
+// testEnvironment returns the environment under test, read from TF_TEST_ENV
+// and defaulting to staging.
+func testEnvironment() string {
+	if env := os.Getenv("TF_TEST_ENV"); env != "" {
+		return env
+	}
+	return "staging"
+}
+
+// isProduction reports whether env is the production environment, under
+// either of the names our naming convention allows.
+func isProduction(env string) bool {
+	return env == "prod" || env == "production"
+}
+
+// envFlag reports whether the named environment variable is set to a true
+// value such as "true" or "1".
+func envFlag(name string) bool {
+	enabled, err := strconv.ParseBool(os.Getenv(name))
+	return err == nil && enabled
+}
+
+// requireApply skips tests that provision real cloud resources unless
+// TF_TEST_APPLY=true, keeping the default `go test ./...` run plan-only.
+func requireApply(t *testing.T) {
+	t.Helper()
+
+	if !envFlag("TF_TEST_APPLY") {
+		t.Skip("set TF_TEST_APPLY=true to run tests that apply to the cloud")
+	}
+}
+
+// environmentDir returns the Terraform directory for env, failing the test
+// if it does not exist.
+func environmentDir(t *testing.T, env string) string {
+	t.Helper()
+
+	dir := filepath.Join(environmentsRoot, env)
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatalf("environment %q not found at %s: %v", env, dir, err)
+	}
+	if !info.IsDir() {
+		t.Fatalf("environment %q at %s is not a directory", env, dir)
+	}
+	return dir
+}
+
+// copyEnvironment copies the Terraform tree to a temporary directory and
+// returns the copy of env within it, so tests can run init in parallel
+// without sharing a .terraform directory. The copy is removed when the test
+// ends unless TF_TEST_SKIP_DESTROY is set, in which case it holds the state
+// needed to destroy by hand.
+func copyEnvironment(t *testing.T, env string) string {
+	t.Helper()
+
+	environmentDir(t, env)
+	dest := t.TempDir()
+	if envFlag("TF_TEST_SKIP_DESTROY") {
+		dest = os.TempDir()
+	}
+	root, err := files.CopyTerraformFolderToDest(terraformRoot, dest, "terratest")
+	if err != nil {
+		t.Fatalf("copying %s: %v", terraformRoot, err)
+	}
+	dir, err := filepath.Rel(terraformRoot, environmentsRoot)
+	if err != nil {
+		t.Fatalf("locating environments under %s: %v", terraformRoot, err)
+	}
+	return filepath.Join(root, dir, env)
+}
+
+// listEnvironments returns the name of every directory under
+// environmentsRoot that contains at least one .tf file.
+func listEnvironments(t *testing.T) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(environmentsRoot)
+	if err != nil {
+		t.Fatalf("reading %s: %v", environmentsRoot, err)
+	}
+
+	var envs []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if hasTerraformFiles(t, filepath.Join(environmentsRoot, entry.Name())) {
+			envs = append(envs, entry.Name())
+		}
+	}
+	return envs
+}
+
+func hasTerraformFiles(t *testing.T, dir string) bool {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("reading %s: %v", dir, err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".tf") {
+			return true
+		}
+	}
+	return false
+}
+
+// TestAllEnvironments plans every environment in the cloud under test.
+func TestAllEnvironments(t *testing.T) {
+	verifier := requireCloudAuth(t)
+
+	for _, env := range listEnvironments(t) {
+		env := env
+		if cloudOf(env) != testCloud() {
+			continue
+		}
+		t.Run(env, func(t *testing.T) {
+			terraformOptions := localOptions(t, verifier, env, nil)
+
+			terraform.Init(t, terraformOptions)
+			terraform.Validate(t, terraformOptions)
+			terraform.Plan(t, terraformOptions)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+
+	"terraform-tests/azureverify"
+)
+
+// deployedTags are the tag keys every environment's defaults and the module
+// put on its resource group. requiredTags and resourceGroupNamePattern hold
+// for the groups the tests create, not for the environments' own default
+// names and tags, so deployed groups are only held to these.
+var deployedTags = []string{"Environment", "ManagedBy"}
+
+// deployedNamePattern returns the naming scheme of env's own resource
+// groups: rg-<env>, as in the environments' defaults, optionally followed by
+// a dash and a lowercase suffix.
+func deployedNamePattern(env string) string {
+	return `^rg-` + regexp.QuoteMeta(env) + `(-[a-z0-9-]+)?$`
+}
+
+// TestExistingInfrastructure checks the deployed environment as recorded in
+// its remote state: every resource group in state must exist in Azure, be
+// named by deployedNamePattern, carry deployedTags with Environment naming
+// the environment, and production groups must be locked. It only runs init and show, never plan, apply or destroy,
+// so it is safe against live environments. Set TF_TEST_READONLY=true to
+// enable it.
+func TestExistingInfrastructure(t *testing.T) {
+	if !envFlag("TF_TEST_READONLY") {
+		t.Skip("set TF_TEST_READONLY=true to check deployed infrastructure read-only")
+	}
+	subscriptionID := requireAzureAuth(t)
+
+	// Init runs in a copy, keeping .terraform out of the checked-in tree.
+	terraformOptions := withAzureRetryableErrors(terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: copyEnvironment(t, testEnvironment()),
+	}))
+	withBackendConfig(t, terraformOptions, testEnvironment()+".tfstate")
+	if len(terraformOptions.BackendConfig) == 0 {
+		t.Fatal("no remote backend configured; set TF_BACKEND_CONFIG_FILE or the TF_BACKEND_* variables")
+	}
+
+	terraform.Init(t, terraformOptions)
+	resources := managedResources(showState(t, terraformOptions))
+	if len(resources) == 0 {
+		t.Fatalf("state for %s has no managed resources", testEnvironment())
+	}
+
+	for _, resource := range resources {
+		if resource.Type != "azurerm_resource_group" {
+			continue
+		}
+		name, _ := resource.AttributeValues["name"].(string)
+		t.Run(resource.Address, func(t *testing.T) {
+			group, err := azureverify.GetResourceGroupE(subscriptionID, name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			AssertNamingConvention(t, name, deployedNamePattern(testEnvironment()))
+			azureverify.AssertRequiredTags(t, subscriptionID, name, deployedTags)
+			if tag := group.Tags["Environment"]; tag != nil && *tag != testEnvironment() {
+				t.Errorf("resource group %s has Environment tag %q, want %q", name, *tag, testEnvironment())
+			}
+			if isProduction(testEnvironment()) {
+				azureverify.AssertResourceLock(t, subscriptionID, name, productionLockLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+// fixturesDir holds the YAML test cases run by TestFromFixtures.
+const fixturesDir = "fixtures"
+
+// namePlaceholder in a fixture's vars or expected outputs stands for the
+// unique resource group name generated for each run.
+const namePlaceholder = "${name}"
+
+// TestCase describes a Terraform run and the outputs it must produce.
+type TestCase struct {
+	// TerraformDir is relative to this package, like the other tests, and
+	// must be an environment directory under environmentsRoot.
+	TerraformDir    string                 `yaml:"terraform_dir"`
+	Vars            map[string]interface{} `yaml:"vars"`
+	ExpectedOutputs map[string]string      `yaml:"expected_outputs"`
+}
+
+// environment returns the name of the environment tc applies, which the
+// run copies with copyEnvironment rather than applying TerraformDir itself.
+func (tc TestCase) environment() string {
+	return filepath.Base(tc.TerraformDir)
+}
+
+// LoadTestCase reads a TestCase from a YAML file.
+func LoadTestCase(path string) (TestCase, error) {
+	var tc TestCase
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tc, fmt.Errorf("reading fixture: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &tc); err != nil {
+		return tc, fmt.Errorf("parsing fixture %s: %w", path, err)
+	}
+	if tc.TerraformDir == "" {
+		return tc, fmt.Errorf("fixture %s: terraform_dir is required", path)
+	}
+	if filepath.Dir(filepath.Clean(tc.TerraformDir)) != filepath.Clean(environmentsRoot) {
+		return tc, fmt.Errorf("fixture %s: terraform_dir %s is not an environment under %s", path, tc.TerraformDir, environmentsRoot)
+	}
+	return tc, nil
+}
+
+// fixtureFiles returns every .yaml and .yml file in fixturesDir.
+func fixtureFiles(t *testing.T) []string {
+	t.Helper()
+
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(fixturesDir, pattern))
+		if err != nil {
+			t.Fatalf("listing fixtures: %v", err)
+		}
+		files = append(files, matches...)
+	}
+	return files
+}
+
+// expandName returns value with namePlaceholder replaced by name in every
+// string, including those nested in maps and lists.
+func expandName(value interface{}, name string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return strings.ReplaceAll(v, namePlaceholder, name)
+	case map[string]interface{}:
+		expanded := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			expanded[key] = expandName(item, name)
+		}
+		return expanded
+	case []interface{}:
+		expanded := make([]interface{}, len(v))
+		for i, item := range v {
+			expanded[i] = expandName(item, name)
+		}
+		return expanded
+	}
+	return value
+}
+
+// fixtureOptions builds the options for tc like basicTerraformOptions does:
+// a unique name substituted for namePlaceholder, the created tag the
+// sweeper relies on, and the environment's workspace and backend key.
+func fixtureOptions(t *testing.T, verifier cloudVerifier, tc TestCase) (*terraform.Options, string) {
+	env := tc.environment()
+	name := uniqueName(env + "-rg-terratest")
+
+	vars := expandName(tc.Vars, name).(map[string]interface{})
+	tags, _ := vars["tags"].(map[string]interface{})
+	if tags == nil {
+		tags = map[string]interface{}{}
+	}
+	tags[createdTag] = time.Now().UTC().Format(time.RFC3339)
+	vars["tags"] = tags
+
+	return environmentOptions(t, verifier, env, name, vars), name
+}
+
+// TestFromFixtures applies each fixture as a subtest and checks its expected
+// outputs. Add coverage by dropping a new YAML file into fixtures/.
+func TestFromFixtures(t *testing.T) {
+	requireApply(t)
+	verifier := azureVerifier{subscriptionID: requireAzureAuth(t)}
+
+	for _, path := range fixtureFiles(t) {
+		path := path
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		t.Run(name, func(t *testing.T) {
+			tc, err := LoadTestCase(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			terraformOptions, resourceName := fixtureOptions(t, verifier, tc)
+
+			defer cleanupOnExit(t, terraformOptions)
+			trackForInterrupt(t, terraformOptions)
+			initWorkspace(t, terraformOptions)
+			terraform.Apply(t, terraformOptions)
+
+			for output, want := range tc.ExpectedOutputs {
+				want = strings.ReplaceAll(want, namePlaceholder, resourceName)
+				assert.Equal(t, want, terraform.Output(t, terraformOptions, output), "output %q", output)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// terraformRoot is the top of the Terraform tree, relative to this package.
+const terraformRoot = "../../terraform"
+
+// terraformInstallURL is shown when tests needing terraform skip without it.
+const terraformInstallURL = "https://developer.hashicorp.com/terraform/install"
+
+// initWithoutBackend initializes providers and modules for opts without
+// configuring the remote backend, which is all validation needs.
+func initWithoutBackend(t *testing.T, opts *terraform.Options) {
+	t.Helper()
+	terraform.RunTerraformCommand(t, opts, "init", "-backend=false", "-input=false")
+}
+
+// TestTerraformFmt fails listing every file under terraform/ that `terraform
+// fmt` would rewrite. Files are only checked, never modified.
+func TestTerraformFmt(t *testing.T) {
+	requireTool(t, "terraform", terraformInstallURL)
+	terraformOptions := &terraform.Options{TerraformDir: terraformRoot}
+
+	output, err := terraform.RunTerraformCommandAndGetStdoutE(t, terraformOptions, "fmt", "-check", "-recursive", "-list=true")
+	if err == nil {
+		return
+	}
+
+	files := strings.Fields(output)
+	if len(files) == 0 {
+		t.Fatalf("terraform fmt -check failed: %v", err)
+	}
+	t.Errorf("files need terraform fmt (run `terraform fmt -recursive terraform/`):\n  %s", strings.Join(files, "\n  "))
+}
+
+// TestTerraformValidate runs terraform validate in a copy of every
+// environment, so init leaves nothing behind in the checked-in tree.
+func TestTerraformValidate(t *testing.T) {
+	requireTool(t, "terraform", terraformInstallURL)
+
+	for _, env := range listEnvironments(t) {
+		env := env
+		t.Run(env, func(t *testing.T) {
+			terraformOptions := &terraform.Options{TerraformDir: copyEnvironment(t, env)}
+
+			initWithoutBackend(t, terraformOptions)
+			terraform.Validate(t, terraformOptions)
+		})
+	}
+}
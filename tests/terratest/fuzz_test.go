@@ -0,0 +1,103 @@
+package test
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// maxResourceGroupNameLength is Azure's limit on resource group names.
+const maxResourceGroupNameLength = 90
+
+// defaultFuzzCases is the number of random cases TestFuzzInputs plans in
+// addition to the shortest and longest valid names.
+const defaultFuzzCases = 3
+
+// fuzzNameChars are the characters our naming convention allows after the
+// environment prefix; Azure itself allows a superset.
+const fuzzNameChars = "abcdefghijklmnopqrstuvwxyz0123456789-"
+
+// fuzzInput is one generated set of variables for TestFuzzInputs.
+type fuzzInput struct {
+	resourceGroupName string
+	location          string
+}
+
+// fuzzInputs returns the boundary cases, a name of the minimum and of the
+// maximum valid length, followed by n random ones, all drawn from rng.
+func fuzzInputs(rng *rand.Rand, n int) []fuzzInput {
+	prefix := testEnvironment() + "-"
+	lengths := []int{len(prefix) + 1, maxResourceGroupNameLength}
+	for i := 0; i < n; i++ {
+		lengths = append(lengths, len(prefix)+1+rng.Intn(maxResourceGroupNameLength-len(prefix)))
+	}
+
+	inputs := make([]fuzzInput, 0, len(lengths))
+	for _, length := range lengths {
+		name := []byte(prefix)
+		for len(name) < length {
+			name = append(name, fuzzNameChars[rng.Intn(len(fuzzNameChars))])
+		}
+		inputs = append(inputs, fuzzInput{
+			resourceGroupName: string(name),
+			location:          allowedLocations[rng.Intn(len(allowedLocations))],
+		})
+	}
+	return inputs
+}
+
+// TestFuzzInputs plans the environment with generated but valid inputs:
+// resource group names from the shortest to the longest Azure accepts, in
+// allowed locations. Each case must plan without error. The generator is
+// seeded from TF_TEST_FUZZ_SEED, or the clock if unset, and the seed is
+// logged so a failing run can be repeated. TF_TEST_FUZZ_CASES sets the
+// number of random cases.
+func TestFuzzInputs(t *testing.T) {
+	subscriptionID := requireAzureAuth(t)
+
+	seed := time.Now().UnixNano()
+	if raw := os.Getenv("TF_TEST_FUZZ_SEED"); raw != "" {
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			t.Fatalf("parsing TF_TEST_FUZZ_SEED %q: %v", raw, err)
+		}
+		seed = value
+	}
+	cases := defaultFuzzCases
+	if raw := os.Getenv("TF_TEST_FUZZ_CASES"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			t.Fatalf("parsing TF_TEST_FUZZ_CASES %q: %v", raw, err)
+		}
+		cases = value
+	}
+	t.Logf("fuzz seed %d; set TF_TEST_FUZZ_SEED=%d to repeat this run", seed, seed)
+
+	terraformOptions := planTerraformOptions(t, azureVerifier{subscriptionID})
+	terraform.Init(t, terraformOptions)
+
+	for i, input := range fuzzInputs(rand.New(rand.NewSource(seed)), cases) {
+		input := input
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			t.Logf("resource_group_name=%q (%d chars) location=%q", input.resourceGroupName, len(input.resourceGroupName), input.location)
+
+			caseOptions := *terraformOptions
+			caseOptions.Vars = map[string]interface{}{}
+			for key, value := range terraformOptions.Vars {
+				caseOptions.Vars[key] = value
+			}
+			caseOptions.Vars["resource_group_name"] = input.resourceGroupName
+			caseOptions.Vars["location"] = input.location
+
+			exitCode, err := terraform.PlanExitCodeE(t, &caseOptions)
+			if err != nil || (exitCode != 0 && exitCode != 2) {
+				t.Fatalf("plan failed with exit code %d: %v", exitCode, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// graphRootNode is the node `terraform graph -type=plan` connects to every
+// top-level object; anything it cannot reach is never planned.
+const graphRootNode = "[root] root"
+
+// dependencyGraph is the part of a DOT graph from `terraform graph` that we
+// check: its nodes with their attributes, and edges from each node to the
+// nodes it depends on.
+type dependencyGraph struct {
+	nodes map[string]map[string]string
+	edges map[string][]string
+}
+
+// parseGraphDOT parses the DOT output of `terraform graph`. It supports the
+// subset of DOT that Terraform writes, one statement per line: node and edge
+// statements with optional attribute lists, graph attributes and nested
+// subgraphs, which are flattened. Lines outside the digraph are ignored.
+func parseGraphDOT(dot string) (*dependencyGraph, error) {
+	graph := &dependencyGraph{nodes: map[string]map[string]string{}, edges: map[string][]string{}}
+	depth := 0
+	for i, raw := range strings.Split(dot, "\n") {
+		line := strings.TrimSpace(raw)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "digraph") || strings.HasPrefix(line, "subgraph"):
+			if strings.HasSuffix(line, "{") {
+				depth++
+			}
+			continue
+		case line == "}":
+			depth--
+			continue
+		case depth == 0 || !strings.HasPrefix(line, `"`):
+			// Outside the graph, or a graph attribute such as rankdir.
+			continue
+		}
+
+		from, rest, err := readQuoted(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		graph.addNode(from)
+
+		rest = strings.TrimSpace(rest)
+		if strings.HasPrefix(rest, "->") {
+			to, tail, err := readQuoted(strings.TrimSpace(strings.TrimPrefix(rest, "->")))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			graph.addNode(to)
+			graph.edges[from] = append(graph.edges[from], to)
+			rest = strings.TrimSpace(tail)
+			if !strings.HasPrefix(rest, "[") {
+				continue
+			}
+			// Edge attributes carry nothing we check.
+			if _, err := parseAttributes(rest); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			continue
+		}
+
+		attrs, err := parseAttributes(rest)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		for key, value := range attrs {
+			graph.nodes[from][key] = value
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced braces in graph")
+	}
+	return graph, nil
+}
+
+func (g *dependencyGraph) addNode(id string) {
+	if _, ok := g.nodes[id]; !ok {
+		g.nodes[id] = map[string]string{}
+	}
+}
+
+// readQuoted reads a double-quoted DOT ID from the start of s, returning it
+// unescaped along with the rest of s.
+func readQuoted(s string) (string, string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", "", fmt.Errorf("expected quoted ID at %q", s)
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			return b.String(), s[i+1:], nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", "", fmt.Errorf("unterminated quoted ID in %q", s)
+}
+
+// parseAttributes parses an attribute list such as
+// [label = "a", shape = "box"]. An empty string has no attributes.
+func parseAttributes(s string) (map[string]string, error) {
+	attrs := map[string]string{}
+	s = strings.TrimSuffix(strings.TrimSpace(s), ";")
+	if s == "" {
+		return attrs, nil
+	}
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("expected attribute list at %q", s)
+	}
+	s = strings.TrimSpace(s[1 : len(s)-1])
+	for s != "" {
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("expected key = value at %q", s)
+		}
+		key := strings.TrimSpace(s[:eq])
+		s = strings.TrimSpace(s[eq+1:])
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			var err error
+			if value, s, err = readQuoted(s); err != nil {
+				return nil, err
+			}
+		} else {
+			end := strings.IndexAny(s, ", ")
+			if end < 0 {
+				end = len(s)
+			}
+			value, s = s[:end], s[end:]
+		}
+		attrs[key] = value
+		s = strings.TrimLeft(s, ", ")
+	}
+	return attrs, nil
+}
+
+// resources returns the IDs of the graph's resource and data source nodes,
+// which Terraform draws as boxes, sorted.
+func (g *dependencyGraph) resources() []string {
+	var ids []string
+	for id, attrs := range g.nodes {
+		if attrs["shape"] == "box" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// findCycle returns the nodes of one dependency cycle, starting and ending
+// with the same node, or nil if the graph is acyclic.
+func (g *dependencyGraph) findCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := map[string]int{}
+	var stack []string
+
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, dep := range g.edges[id] {
+			switch state[dep] {
+			case visiting:
+				for i, onStack := range stack {
+					if onStack == dep {
+						return append(append([]string{}, stack[i:]...), dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+		return nil
+	}
+
+	ids := make([]string, 0, len(g.nodes))
+	for id := range g.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if state[id] == unvisited {
+			if cycle := visit(id); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// unreachableFrom returns the sorted resources that cannot be reached by
+// following edges from root.
+func (g *dependencyGraph) unreachableFrom(root string) []string {
+	seen := map[string]bool{root: true}
+	queue := []string{root}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, dep := range g.edges[id] {
+			if !seen[dep] {
+				seen[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	var unreachable []string
+	for _, id := range g.resources() {
+		if !seen[id] {
+			unreachable = append(unreachable, id)
+		}
+	}
+	return unreachable
+}
+
+// label returns the human-readable name of a node.
+func (g *dependencyGraph) label(id string) string {
+	if label := g.nodes[id]["label"]; label != "" {
+		return label
+	}
+	return id
+}
+
+func (g *dependencyGraph) labels(ids []string) []string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = g.label(id)
+	}
+	return names
+}
+
+// TestDependencyGraph checks each environment's plan graph for dependency
+// cycles and for resources the root node cannot reach, either of which means
+// a module or resource was wired up wrongly. Each environment is
+// initialized in a copy, leaving the checked-in tree untouched.
+func TestDependencyGraph(t *testing.T) {
+	requireTool(t, "terraform", terraformInstallURL)
+
+	for _, env := range listEnvironments(t) {
+		env := env
+		t.Run(env, func(t *testing.T) {
+			terraformOptions := &terraform.Options{TerraformDir: copyEnvironment(t, env)}
+
+			initWithoutBackend(t, terraformOptions)
+			dot := terraform.RunTerraformCommand(t, terraformOptions, "graph", "-type=plan")
+			graph, err := parseGraphDOT(dot)
+			if err != nil {
+				t.Fatalf("parsing terraform graph output: %v", err)
+			}
+			if _, ok := graph.nodes[graphRootNode]; !ok {
+				t.Fatalf("terraform graph output has no %q node", graphRootNode)
+			}
+
+			if cycle := graph.findCycle(); cycle != nil {
+				t.Errorf("dependency cycle: %s", strings.Join(graph.labels(cycle), " -> "))
+			}
+			if unreachable := graph.unreachableFrom(graphRootNode); len(unreachable) > 0 {
+				t.Errorf("resources not reachable from the root: %s", strings.Join(graph.labels(unreachable), ", "))
+			}
+		})
+	}
+}
+
+func TestParseGraphDOT(t *testing.T) {
+	dot := `digraph {
+	compound = "true"
+	subgraph "root" {
+		"[root] a (expand)" [label = "a", shape = "box"]
+		"[root] b (expand)" [label = "b", shape = "box"]
+		"[root] c (expand)" [label = "c", shape = "box"]
+		"[root] provider[\"registry.terraform.io/hashicorp/azurerm\"]" [label = "provider", shape = "diamond"]
+		"[root] root" -> "[root] a (expand)"
+		"[root] a (expand)" -> "[root] b (expand)"
+		"[root] b (expand)" -> "[root] provider[\"registry.terraform.io/hashicorp/azurerm\"]"
+		"[root] c (expand)" -> "[root] c (expand)"
+	}
+}`
+	graph, err := parseGraphDOT(dot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(graph.resources()); got != 3 {
+		t.Errorf("got %d resources, want 3", got)
+	}
+	if got := strings.Join(graph.labels(graph.unreachableFrom(graphRootNode)), ", "); got != "c" {
+		t.Errorf("unreachable resources = %q, want %q", got, "c")
+	}
+	if got := strings.Join(graph.labels(graph.findCycle()), " -> "); got != "c -> c" {
+		t.Errorf("cycle = %q", got)
+	}
+}
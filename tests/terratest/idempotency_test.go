@@ -0,0 +1,24 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// TestIdempotency applies the environment and then plans again, failing if
+// the second plan wants to change anything.
+func TestIdempotency(t *testing.T) {
+	requireApply(t)
+	verifier := requireCloudAuth(t)
+	t.Parallel()
+
+	terraformOptions := basicTerraformOptions(t, verifier)
+
+	defer cleanupOnExit(t, terraformOptions)
+	trackForInterrupt(t, terraformOptions)
+	initWorkspace(t, terraformOptions)
+	terraform.Apply(t, terraformOptions)
+
+	AssertPlanChanges(t, terraformOptions, PlanSummary{})
+}
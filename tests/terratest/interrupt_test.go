@@ -0,0 +1,186 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// applied holds the options of every test that may have live resources,
+// so an interrupted run can destroy them before exiting.
+var applied = struct {
+	sync.Mutex
+	opts map[*terraform.Options]string // -> test name
+}{opts: map[*terraform.Options]string{}}
+
+// interruptExitGrace is how long, after destroying, the handler waits for
+// the interrupted run to end by itself before exiting.
+const interruptExitGrace = time.Minute
+
+var (
+	// interrupted is closed, with applied locked, when a signal arrives.
+	interrupted = make(chan struct{})
+	// interruptCleanedUp is closed once the handler's destroys are done.
+	interruptCleanedUp = make(chan struct{})
+)
+
+// interruptReceived reports whether the run has been interrupted.
+func interruptReceived() bool {
+	select {
+	case <-interrupted:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitForInterruptCleanup blocks until the handler has finished destroying.
+// Tests wait in their cleanup rather than racing its destroy, which also
+// keeps their working copies from being removed while it runs.
+func waitForInterruptCleanup(t *testing.T, opts *terraform.Options) {
+	t.Logf("run interrupted, leaving %s to the signal handler", opts.TerraformDir)
+	<-interruptCleanedUp
+}
+
+// trackForInterrupt registers opts to be destroyed if the run receives
+// SIGINT or SIGTERM before the test finishes. Call it next to the deferred
+// cleanupOnExit, before applying; with t.Cleanup, call it before registering
+// the destroy, so the untracking cleanup runs after it. It is deregistered
+// once destroyUnlessSkipped has destroyed opts, or when the test ends. Once the run has been
+// interrupted it skips the test instead, so nothing new is applied.
+func trackForInterrupt(t *testing.T, opts *terraform.Options) {
+	applied.Lock()
+	if interruptReceived() {
+		applied.Unlock()
+		t.Skip("run interrupted")
+	}
+	applied.opts[opts] = t.Name()
+	applied.Unlock()
+
+	// Registered after copyEnvironment's TempDir, so it runs before the
+	// directory is removed.
+	t.Cleanup(func() { untrackForInterrupt(opts) })
+}
+
+func untrackForInterrupt(opts *terraform.Options) {
+	applied.Lock()
+	delete(applied.opts, opts)
+	applied.Unlock()
+}
+
+// handleInterrupts destroys every tracked test's resources on SIGINT or
+// SIGTERM, instead of leaving half-built resource groups behind. Tests
+// reaching their cleanup meanwhile wait for it, then finish, so the run ends
+// normally and still writes its reports; if it has not ended within
+// interruptExitGrace of the destroys, the process exits. While the destroys
+// run, a second signal kills the run at once.
+//
+// The returned function stops handling signals, blocking until any destroys
+// already started have finished, and reports whether the run was
+// interrupted.
+func handleInterrupts() func() bool {
+	signals := make(chan os.Signal, 1)
+	stopped := make(chan struct{})
+	finished := make(chan struct{})
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		defer close(finished)
+		select {
+		case <-stopped:
+			return
+		case sig := <-signals:
+			signal.Stop(signals)
+			applied.Lock()
+			close(interrupted)
+			applied.Unlock()
+
+			fmt.Fprintf(os.Stderr, "received %s, destroying resources before exiting; signal again to abort\n", sig)
+			destroyTracked()
+			close(interruptCleanedUp)
+			time.AfterFunc(interruptExitGrace, func() {
+				fmt.Fprintf(os.Stderr, "run still going %s after the interrupt, exiting\n", interruptExitGrace)
+				os.Exit(1)
+			})
+		}
+	}()
+
+	return func() bool {
+		close(stopped)
+		<-finished
+		signal.Stop(signals)
+		return interruptReceived()
+	}
+}
+
+// destroyTracked destroys every tracked test's resources concurrently,
+// honouring TF_TEST_SKIP_DESTROY.
+func destroyTracked() {
+	applied.Lock()
+	tracked := make(map[*terraform.Options]string, len(applied.opts))
+	for opts, name := range applied.opts {
+		tracked[opts] = name
+	}
+	applied.Unlock()
+
+	if envFlag("TF_TEST_SKIP_DESTROY") {
+		for opts, name := range tracked {
+			fmt.Fprintf(os.Stderr, "TF_TEST_SKIP_DESTROY is set, leaving resources of %s in %s\n", name, opts.TerraformDir)
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	for opts, name := range tracked {
+		wg.Add(1)
+		go func(opts *terraform.Options, name string) {
+			defer wg.Done()
+
+			// Applies from environmentOptions hold the state lock until
+			// terraform has stopped on the signal, so the destroy waits
+			// for it instead of racing an apply still writing state.
+			if _, err := terraform.DestroyE(&interruptT{name: name}, withStateLock(opts)); err != nil {
+				fmt.Fprintf(os.Stderr, "destroying resources of %s in %s: %v\n", name, opts.TerraformDir, err)
+				return
+			}
+			untrackForInterrupt(opts)
+		}(opts, name)
+	}
+	wg.Wait()
+}
+
+// interruptT lets terratest run outside a test once the run is being torn
+// down. Failures are printed; FailNow ends only the calling goroutine.
+type interruptT struct {
+	name string
+}
+
+func (t *interruptT) Fail()    {}
+func (t *interruptT) FailNow() { runtime.Goexit() }
+
+func (t *interruptT) Fatal(args ...interface{}) {
+	t.Error(args...)
+	t.FailNow()
+}
+
+func (t *interruptT) Fatalf(format string, args ...interface{}) {
+	t.Errorf(format, args...)
+	t.FailNow()
+}
+
+func (t *interruptT) Error(args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "%s: %s", t.name, fmt.Sprintln(args...))
+}
+
+func (t *interruptT) Errorf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "%s: %s\n", t.name, fmt.Sprintf(format, args...))
+}
+
+func (t *interruptT) Name() string { return t.name }
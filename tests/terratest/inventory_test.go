@@ -0,0 +1,101 @@
+package test
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// inventoryItem is one managed resource in the inventory written by
+// WriteInventory.
+type inventoryItem struct {
+	Address  string            `json:"address"`
+	Type     string            `json:"type"`
+	Name     string            `json:"name"`
+	ID       string            `json:"id"`
+	Location string            `json:"location,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+}
+
+// inventory lists every managed resource in the state of opts. Name is the
+// resource's name attribute where it has one, otherwise its name in the
+// configuration.
+func inventory(t *testing.T, opts *terraform.Options) []inventoryItem {
+	t.Helper()
+
+	var items []inventoryItem
+	for _, resource := range managedResources(showState(t, opts)) {
+		item := inventoryItem{
+			Address: resource.Address,
+			Type:    resource.Type,
+			Name:    resource.Name,
+		}
+		values := resource.AttributeValues
+		if name, ok := values["name"].(string); ok && name != "" {
+			item.Name = name
+		}
+		item.ID, _ = values["id"].(string)
+		item.Location, _ = values["location"].(string)
+		if tags, ok := values["tags"].(map[string]interface{}); ok {
+			item.Tags = make(map[string]string, len(tags))
+			for key, value := range tags {
+				item.Tags[key] = fmt.Sprint(value)
+			}
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Address < items[j].Address })
+	return items
+}
+
+// WriteInventory writes the type, name, ID, location and tags of every
+// resource in the state of opts to path, as CSV if path ends in .csv and as
+// JSON otherwise. In CSV, tags are a single column of key=value pairs
+// separated by semicolons.
+func WriteInventory(t *testing.T, opts *terraform.Options, path string) {
+	t.Helper()
+
+	items := inventory(t, opts)
+
+	var data []byte
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		var b strings.Builder
+		w := csv.NewWriter(&b)
+		_ = w.Write([]string{"address", "type", "name", "id", "location", "tags"})
+		for _, item := range items {
+			_ = w.Write([]string{item.Address, item.Type, item.Name, item.ID, item.Location, formatTags(item.Tags)})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			t.Fatalf("encoding inventory: %v", err)
+		}
+		data = []byte(b.String())
+	} else {
+		var err error
+		if data, err = json.MarshalIndent(items, "", "  "); err != nil {
+			t.Fatalf("encoding inventory: %v", err)
+		}
+		data = append(data, '\n')
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("writing inventory: %v", err)
+	}
+	t.Logf("wrote inventory of %d resource(s) to %s", len(items), path)
+}
+
+func formatTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ";")
+}
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/shell"
+)
+
+// tflintOutput is the result printed by `tflint --format json`.
+type tflintOutput struct {
+	Issues []struct {
+		Rule struct {
+			Name     string `json:"name"`
+			Severity string `json:"severity"`
+			Link     string `json:"link"`
+		} `json:"rule"`
+		Message string      `json:"message"`
+		Range   tflintRange `json:"range"`
+	} `json:"issues"`
+	Errors []struct {
+		Message string      `json:"message"`
+		Range   tflintRange `json:"range"`
+	} `json:"errors"`
+}
+
+type tflintRange struct {
+	Filename string `json:"filename"`
+	Start    struct {
+		Line int `json:"line"`
+	} `json:"start"`
+}
+
+func (r tflintRange) String() string {
+	return fmt.Sprintf("%s:%d", r.Filename, r.Start.Line)
+}
+
+// TestTFLint runs tflint over terraform/ and fails listing every finding. A
+// .tflint.hcl at the repository root is used when present.
+func TestTFLint(t *testing.T) {
+	requireTool(t, "tflint", "https://github.com/terraform-linters/tflint")
+
+	// tflint rejects --chdir with --recursive, so run it from terraform/
+	// and give the config as an absolute path.
+	args := []string{"--recursive", "--format", "json"}
+	if configPath, err := filepath.Abs(filepath.Join(repoRoot, ".tflint.hcl")); err == nil {
+		if _, err := os.Stat(configPath); err == nil {
+			args = append(args, "--config", configPath)
+		}
+	}
+
+	// tflint exits non-zero when it finds issues, so parse stdout first.
+	output, runErr := shell.RunCommandAndGetStdOutE(t, shell.Command{
+		Command:    "tflint",
+		Args:       args,
+		WorkingDir: terraformRoot,
+	})
+
+	var result tflintOutput
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("parsing tflint output (run error: %v): %v", runErr, err)
+	}
+
+	var findings []string
+	for _, issue := range result.Issues {
+		findings = append(findings, fmt.Sprintf("%s: [%s] %s: %s", issue.Range, issue.Rule.Severity, issue.Rule.Name, issue.Message))
+	}
+	for _, lintErr := range result.Errors {
+		findings = append(findings, fmt.Sprintf("%s: [error] %s", lintErr.Range, lintErr.Message))
+	}
+	if len(findings) > 0 {
+		t.Errorf("tflint reported %d finding(s):\n  %s", len(findings), strings.Join(findings, "\n  "))
+	}
+}
@@ -0,0 +1,26 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-tests/azureverify"
+)
+
+// allowedLocations are the Azure regions approved for deployments. Keep them
+// in sync with the location validation in terraform/modules/example.
+var allowedLocations = []string{"eastus", "eastus2", "westus2", "northeurope", "westeurope"}
+
+// AssertAllowedLocation fails the test unless location, in display or short
+// form, is one of the allowed regions.
+func AssertAllowedLocation(t *testing.T, location string, allowed []string) {
+	t.Helper()
+
+	normalized := azureverify.NormalizeLocation(location)
+	for _, candidate := range allowed {
+		if azureverify.NormalizeLocation(candidate) == normalized {
+			return
+		}
+	}
+	t.Errorf("location %q is not an approved region; allowed: %s", location, strings.Join(allowed, ", "))
+}
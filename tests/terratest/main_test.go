@@ -0,0 +1,91 @@
+package test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"terraform-tests/report"
+	"terraform-tests/steps"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+// runTests runs the suite, writing a JUnit XML report to TF_TEST_JUNIT_PATH
+// and per-test duration metrics to TF_TEST_METRICS_PATH when they are set.
+// Reporting forces verbose output because that is the only form in which the
+// testing package prints every test's outcome.
+//
+// For the whole run, SIGINT and SIGTERM destroy the resources of tests that
+// called trackForInterrupt and fail the run; see handleInterrupts.
+func runTests(m *testing.M) (code int) {
+	stopInterrupts := handleInterrupts()
+	defer func() {
+		if stopInterrupts() {
+			code = 1
+		}
+	}()
+
+	// Apply runs check credentials once up front rather than in whichever
+	// parallel test gets there first; see suiteFixture.
+	if envFlag("TF_TEST_APPLY") {
+		sharedSuite()
+	}
+
+	junitPath := os.Getenv("TF_TEST_JUNIT_PATH")
+	metricsPath := os.Getenv("TF_TEST_METRICS_PATH")
+	if junitPath == "" && metricsPath == "" {
+		return m.Run()
+	}
+
+	flag.Parse()
+	if v := flag.Lookup("test.v"); v != nil && v.Value.String() == "false" {
+		_ = v.Value.Set("true")
+	}
+
+	collector := report.NewCollector()
+	restore, err := collector.Capture()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "capturing test output: %v\n", err)
+		return 1
+	}
+	startedAt := time.Now().UTC()
+	code = m.Run()
+	restore()
+
+	if junitPath != "" {
+		if err := report.NewJUnitReporterFor("terraform-tests", collector).WriteFile(junitPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+		}
+	}
+	if metricsPath != "" {
+		metrics := report.Metrics{
+			Environment: testEnvironment(),
+			StartedAt:   startedAt,
+			Tests:       report.NewTestMetrics(collector.Results(), stepPhases),
+		}
+		if err := report.WriteMetrics(metricsPath, metrics); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+		}
+	}
+	return code
+}
+
+// stepPhases reports the phases a test recorded with the steps package.
+func stepPhases(testName string) []report.PhaseMetrics {
+	var phases []report.PhaseMetrics
+	for _, timing := range steps.Timings(testName) {
+		phases = append(phases, report.PhaseMetrics{
+			Name:            timing.Name,
+			DurationSeconds: timing.Duration.Seconds(),
+			Failed:          timing.Failed,
+		})
+	}
+	return phases
+}
@@ -0,0 +1,56 @@
+package test
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+)
+
+// resourceGroupNamePattern is the naming standard for resource groups: an
+// environment prefix followed by lowercase alphanumerics and dashes.
+const resourceGroupNamePattern = `^(dev|staging|prod|production)-[a-z0-9-]+$`
+
+var (
+	namingPatternsMu sync.Mutex
+	namingPatterns   = map[string]*regexp.Regexp{}
+)
+
+// uniqueName appends a short random suffix to prefix so concurrent runs do
+// not collide on the same Azure resource names.
+func uniqueName(prefix string) string {
+	return fmt.Sprintf("%s-%s", prefix, strings.ToLower(random.UniqueId()))
+}
+
+// AssertNamingConvention fails the test unless name matches pattern. Each
+// pattern is compiled once and reused; an invalid pattern fails the test.
+func AssertNamingConvention(t *testing.T, name, pattern string) {
+	t.Helper()
+
+	re, err := compileNamingPattern(pattern)
+	if err != nil {
+		t.Errorf("invalid naming pattern %q: %v", pattern, err)
+		return
+	}
+	if !re.MatchString(name) {
+		t.Errorf("name %q does not match naming convention %q", name, pattern)
+	}
+}
+
+func compileNamingPattern(pattern string) (*regexp.Regexp, error) {
+	namingPatternsMu.Lock()
+	defer namingPatternsMu.Unlock()
+
+	if re, ok := namingPatterns[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	namingPatterns[pattern] = re
+	return re, nil
+}
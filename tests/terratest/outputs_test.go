@@ -0,0 +1,100 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// outputMeta is one entry of `terraform output -json`.
+type outputMeta struct {
+	Sensitive bool            `json:"sensitive"`
+	Type      json.RawMessage `json:"type"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// outputsJSON returns every root module output with its metadata.
+func outputsJSON(t *testing.T, opts *terraform.Options) map[string]outputMeta {
+	t.Helper()
+
+	raw := terraform.OutputJson(t, opts, "")
+	var outputs map[string]outputMeta
+	if err := json.Unmarshal([]byte(raw), &outputs); err != nil {
+		t.Fatalf("parsing terraform output JSON: %v", err)
+	}
+	return outputs
+}
+
+// AssertOutputSensitive fails the test unless outputName exists and its
+// sensitive flag equals wantSensitive.
+func AssertOutputSensitive(t *testing.T, opts *terraform.Options, outputName string, wantSensitive bool) {
+	t.Helper()
+
+	output, ok := outputsJSON(t, opts)[outputName]
+	if !ok {
+		t.Fatalf("output %q not found", outputName)
+	}
+	if output.Sensitive != wantSensitive {
+		t.Errorf("output %q has sensitive = %t, want %t", outputName, output.Sensitive, wantSensitive)
+	}
+}
+
+// OutputList returns a list output as strings, failing the test if it is
+// missing or not a list.
+func OutputList(t *testing.T, opts *terraform.Options, name string) []string {
+	t.Helper()
+
+	values, err := terraform.OutputListE(t, opts, name)
+	if err != nil {
+		t.Fatalf("reading list output %q: %v", name, err)
+	}
+	return values
+}
+
+// OutputMap returns a map output with its values as strings, failing the
+// test if it is missing or not a map.
+func OutputMap(t *testing.T, opts *terraform.Options, name string) map[string]string {
+	t.Helper()
+
+	values, err := terraform.OutputMapE(t, opts, name)
+	if err != nil {
+		t.Fatalf("reading map output %q: %v", name, err)
+	}
+	return values
+}
+
+// OutputJSON unmarshals an output of any type, such as a list of objects,
+// into dest, which must be a pointer.
+func OutputJSON(t *testing.T, opts *terraform.Options, name string, dest interface{}) {
+	t.Helper()
+
+	if err := terraform.OutputStructE(t, opts, name, dest); err != nil {
+		t.Fatalf("decoding output %q: %v", name, err)
+	}
+}
+
+// assertSubnetIDs checks the subnet_ids output when the environment exposes
+// one: it must list at least one subnet, with no duplicates or blanks.
+func assertSubnetIDs(t *testing.T, opts *terraform.Options) {
+	t.Helper()
+
+	if _, ok := outputsJSON(t, opts)["subnet_ids"]; !ok {
+		t.Log("no subnet_ids output, skipping subnet check")
+		return
+	}
+
+	subnetIDs := OutputList(t, opts, "subnet_ids")
+	if len(subnetIDs) == 0 {
+		t.Error("subnet_ids output is empty")
+	}
+	seen := map[string]bool{}
+	for i, id := range subnetIDs {
+		if id == "" {
+			t.Errorf("subnet_ids[%d] is empty", i)
+		} else if seen[id] {
+			t.Errorf("subnet_ids lists %q more than once", id)
+		}
+		seen[id] = true
+	}
+}
@@ -0,0 +1,150 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"gopkg.in/yaml.v3"
+)
+
+// parityFile lists the differences TestEnvironmentParity accepts.
+const parityFile = "parity.yaml"
+
+// parityEnvironments are the environments TestEnvironmentParity compares.
+var parityEnvironments = [2]string{"staging", "production"}
+
+// instanceKey matches resource instance keys such as [0] or ["a"].
+var instanceKey = regexp.MustCompile(`\[[^\]]*\]`)
+
+// parityConfig is the contents of parityFile.
+type parityConfig struct {
+	AllowedDifferences []string `yaml:"allowed_differences"`
+}
+
+// planShape maps each resource in a plan, without instance keys, to the set
+// of top-level attributes it sets, known or not. Values are ignored.
+func planShape(planJSON string) (map[string]map[string]bool, error) {
+	var plan tfjson.Plan
+	if err := json.Unmarshal([]byte(planJSON), &plan); err != nil {
+		return nil, fmt.Errorf("parsing plan JSON: %w", err)
+	}
+
+	shape := map[string]map[string]bool{}
+	for _, change := range plan.ResourceChanges {
+		address := instanceKey.ReplaceAllString(change.Address, "")
+		attrs, ok := shape[address]
+		if !ok {
+			attrs = map[string]bool{}
+			shape[address] = attrs
+		}
+		if change.Change == nil {
+			continue
+		}
+		if after, ok := change.Change.After.(map[string]interface{}); ok {
+			for name, value := range after {
+				if value != nil {
+					attrs[name] = true
+				}
+			}
+		}
+		if unknown, ok := change.Change.AfterUnknown.(map[string]interface{}); ok {
+			for name, value := range unknown {
+				if value == true {
+					attrs[name] = true
+				}
+			}
+		}
+	}
+	return shape, nil
+}
+
+// shapeDifferences returns the resources and attributes of shape a that are
+// missing from b, keyed as in parityFile, with a message for each.
+func shapeDifferences(a, b map[string]map[string]bool, nameA, nameB string) map[string]string {
+	differences := map[string]string{}
+	for address, attrs := range a {
+		other, ok := b[address]
+		if !ok {
+			differences[address] = fmt.Sprintf("%s is in %s but not %s", address, nameA, nameB)
+			continue
+		}
+		for attr := range attrs {
+			if !other[attr] {
+				key := address + "." + attr
+				differences[key] = fmt.Sprintf("%s is set in %s but not %s", key, nameA, nameB)
+			}
+		}
+	}
+	return differences
+}
+
+// parityPlan plans a copy of env from empty state and returns the plan's
+// shape.
+func parityPlan(t *testing.T, verifier cloudVerifier, env string) map[string]map[string]bool {
+	t.Helper()
+
+	terraformOptions := localOptions(t, verifier, env, nil)
+	terraformOptions.PlanFilePath = filepath.Join(t.TempDir(), "tfplan")
+
+	shape, err := planShape(terraform.InitAndPlanAndShow(t, terraformOptions))
+	if err != nil {
+		t.Fatalf("%s: %v", env, err)
+	}
+	return shape
+}
+
+// TestEnvironmentParity plans staging and production with their own
+// defaults and fails if one has resources or attributes the other lacks,
+// unless parity.yaml allows the difference. Allowed differences that no
+// longer occur are logged so the list can be pruned.
+func TestEnvironmentParity(t *testing.T) {
+	verifier := azureVerifier{subscriptionID: requireAzureAuth(t)}
+
+	data, err := os.ReadFile(parityFile)
+	if err != nil {
+		t.Fatalf("reading parity config: %v", err)
+	}
+	var config parityConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		t.Fatalf("parsing %s: %v", parityFile, err)
+	}
+	allowed := map[string]bool{}
+	for _, key := range config.AllowedDifferences {
+		allowed[key] = true
+	}
+
+	first, second := parityEnvironments[0], parityEnvironments[1]
+	firstShape := parityPlan(t, verifier, first)
+	secondShape := parityPlan(t, verifier, second)
+
+	differences := shapeDifferences(firstShape, secondShape, first, second)
+	for key, message := range shapeDifferences(secondShape, firstShape, second, first) {
+		differences[key] = message
+	}
+
+	var unexpected []string
+	for key, message := range differences {
+		if !allowed[key] {
+			unexpected = append(unexpected, message)
+		}
+		delete(allowed, key)
+	}
+	for key := range allowed {
+		t.Logf("allowed difference %s no longer occurs; consider removing it from %s", key, parityFile)
+	}
+
+	if len(unexpected) > 0 {
+		sort.Strings(unexpected)
+		t.Errorf("%s and %s have diverged:", first, second)
+		for _, message := range unexpected {
+			t.Errorf("  %s", message)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// minPlannedAdds is the number of resources a fresh plan of the environment
+// must create: at least the resource group.
+const minPlannedAdds = 1
+
+// PlanSummary counts the resource changes in a plan, as in Terraform's
+// "Plan: N to add, N to change, N to destroy" line.
+type PlanSummary struct {
+	Add     int
+	Change  int
+	Destroy int
+}
+
+func (s PlanSummary) String() string {
+	return fmt.Sprintf("%d to add, %d to change, %d to destroy", s.Add, s.Change, s.Destroy)
+}
+
+// ParsePlanSummary counts the actions in the resource_changes of a plan in
+// `terraform show -json` form. No-op and read actions are not counted, and a
+// replacement counts as both an add and a destroy.
+func ParsePlanSummary(planJSON string) (PlanSummary, error) {
+	var plan tfjson.Plan
+	if err := json.Unmarshal([]byte(planJSON), &plan); err != nil {
+		return PlanSummary{}, fmt.Errorf("parsing plan JSON: %w", err)
+	}
+
+	var summary PlanSummary
+	for _, change := range plan.ResourceChanges {
+		if change.Change == nil {
+			continue
+		}
+		switch actions := change.Change.Actions; {
+		case actions.Replace():
+			summary.Add++
+			summary.Destroy++
+		case actions.Create():
+			summary.Add++
+		case actions.Update():
+			summary.Change++
+		case actions.Delete():
+			summary.Destroy++
+		}
+	}
+	return summary, nil
+}
+
+// changedResources lists the resource changes in a plan in `terraform show
+// -json` form that ParsePlanSummary counts, as address and actions, such as
+// "azurerm_resource_group.main (update)".
+func changedResources(planJSON string) ([]string, error) {
+	var plan tfjson.Plan
+	if err := json.Unmarshal([]byte(planJSON), &plan); err != nil {
+		return nil, fmt.Errorf("parsing plan JSON: %w", err)
+	}
+
+	var changed []string
+	for _, change := range plan.ResourceChanges {
+		if change.Change == nil || change.Change.Actions.NoOp() || change.Change.Actions.Read() {
+			continue
+		}
+		actions := make([]string, len(change.Change.Actions))
+		for i, action := range change.Change.Actions {
+			actions[i] = string(action)
+		}
+		changed = append(changed, fmt.Sprintf("%s (%s)", change.Address, strings.Join(actions, ", ")))
+	}
+	return changed, nil
+}
+
+// AssertPlanChanges plans opts to a temporary plan file and fails the test,
+// listing the changed resources, unless the plan makes exactly the changes
+// in want. opts is not modified, so a later apply does not pick up the saved
+// plan.
+func AssertPlanChanges(t *testing.T, opts *terraform.Options, want PlanSummary) {
+	t.Helper()
+
+	planJSON := showPlan(t, opts)
+	got, err := ParsePlanSummary(planJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == want {
+		return
+	}
+	changed, err := changedResources(planJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Errorf("plan has %s, want %s:\n  %s", got, want, strings.Join(changed, "\n  "))
+}
+
+// planSummary plans a copy of opts to a temporary plan file and counts its
+// changes.
+func planSummary(t *testing.T, opts *terraform.Options) PlanSummary {
+	t.Helper()
+
+	summary, err := ParsePlanSummary(showPlan(t, opts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return summary
+}
+
+// showPlan plans a copy of opts to a temporary plan file and returns it in
+// `terraform show -json` form.
+func showPlan(t *testing.T, opts *terraform.Options) string {
+	t.Helper()
+
+	planOpts := *opts
+	planOpts.PlanFilePath = filepath.Join(t.TempDir(), "tfplan")
+	return terraform.InitAndPlanAndShow(t, &planOpts)
+}
+
+// TestPlanOnly checks that the environment plans cleanly without applying
+// anything, so it is safe to run on every PR.
+func TestPlanOnly(t *testing.T) {
+	verifier := requireCloudAuth(t)
+
+	terraformOptions := planTerraformOptions(t, verifier)
+
+	if summary := planSummary(t, terraformOptions); summary.Add < minPlannedAdds {
+		t.Errorf("plan has %s, want at least %d to add", summary, minPlannedAdds)
+	}
+}
+
+func TestParsePlanSummary(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []string
+		want    PlanSummary
+	}{
+		{"no changes", nil, PlanSummary{}},
+		{"no-op", []string{`["no-op"]`}, PlanSummary{}},
+		{"read", []string{`["read"]`}, PlanSummary{}},
+		{"create", []string{`["create"]`}, PlanSummary{Add: 1}},
+		{"update", []string{`["update"]`}, PlanSummary{Change: 1}},
+		{"delete", []string{`["delete"]`}, PlanSummary{Destroy: 1}},
+		{"delete then create", []string{`["delete", "create"]`}, PlanSummary{Add: 1, Destroy: 1}},
+		{"create then delete", []string{`["create", "delete"]`}, PlanSummary{Add: 1, Destroy: 1}},
+		{"mixed", []string{`["create"]`, `["create"]`, `["no-op"]`, `["update"]`, `["delete", "create"]`, `["read"]`}, PlanSummary{Add: 3, Change: 1, Destroy: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := make([]string, len(tt.actions))
+			for i, actions := range tt.actions {
+				changes[i] = fmt.Sprintf(`{"address": "azurerm_resource_group.r%d", "change": {"actions": %s}}`, i, actions)
+			}
+			planJSON := fmt.Sprintf(`{"format_version": "1.2", "resource_changes": [%s]}`, strings.Join(changes, ", "))
+
+			got, err := ParsePlanSummary(planJSON)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParsePlanSummary("not json"); err == nil {
+		t.Error("ParsePlanSummary accepted invalid JSON")
+	}
+}
+
+func TestChangedResources(t *testing.T) {
+	planJSON := `{"format_version": "1.2", "resource_changes": [
+		{"address": "azurerm_resource_group.main", "change": {"actions": ["update"]}},
+		{"address": "azurerm_management_lock.main[0]", "change": {"actions": ["delete", "create"]}},
+		{"address": "data.azurerm_client_config.current", "change": {"actions": ["read"]}},
+		{"address": "azurerm_resource_group.other", "change": {"actions": ["no-op"]}}
+	]}`
+
+	got, err := changedResources(planJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"azurerm_resource_group.main (update)", "azurerm_management_lock.main[0] (delete, create)"}
+	if strings.Join(got, "; ") != strings.Join(want, "; ") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// providerOverrideFile is written into the test's copy of the environment by
+// withProviderVersion. Terraform merges *_override.tf files over the rest of
+// the configuration, so the committed constraint is left untouched.
+const providerOverrideFile = "terratest_provider_override.tf"
+
+// providerOverrideTemplate replaces the cloud provider's requirement. The
+// arguments are the provider's local name, source and version constraint.
+const providerOverrideTemplate = `# Written by terraform-tests to pin the provider version; removed when the test ends.
+terraform {
+  required_providers {
+    %s = {
+      source  = %q
+      version = %q
+    }
+  }
+}
+`
+
+// withProviderVersion pins the cloud provider in opts.TerraformDir, a copy
+// from copyEnvironment, to version, which may be an exact version or any
+// constraint. It fails if the copy is already pinned.
+func withProviderVersion(t *testing.T, opts *terraform.Options, version string) *terraform.Options {
+	t.Helper()
+
+	path := filepath.Join(opts.TerraformDir, providerOverrideFile)
+	if _, err := os.Stat(path); err == nil {
+		t.Fatalf("%s already exists; the provider is already pinned", path)
+	}
+
+	provider := expectedProviders[testCloud()]
+	contents := fmt.Sprintf(providerOverrideTemplate, provider.name, provider.source, version)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing provider override: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("removing provider override: %v", err)
+		}
+	})
+	t.Logf("pinned %s to %s in %s", provider.name, version, opts.TerraformDir)
+
+	// The lock file records the previously selected version.
+	opts.Upgrade = true
+	return opts
+}
+
+// TestProviderMatrix plans the environment against each provider version in
+// the comma-separated TF_TEST_PROVIDER_MATRIX, such as "4.30.0,4.31.0", to
+// try a provider bump before changing the committed constraint.
+func TestProviderMatrix(t *testing.T) {
+	matrix := os.Getenv("TF_TEST_PROVIDER_MATRIX")
+	if matrix == "" {
+		t.Skip("set TF_TEST_PROVIDER_MATRIX to a comma-separated list of provider versions to plan against")
+	}
+	if os.Getenv("TF_TEST_PROVIDER_VERSION") != "" {
+		t.Fatal("TF_TEST_PROVIDER_VERSION pins every test; unset it when running TF_TEST_PROVIDER_MATRIX")
+	}
+	verifier := requireCloudAuth(t)
+
+	for _, version := range strings.Split(matrix, ",") {
+		version := strings.TrimSpace(version)
+		t.Run(version, func(t *testing.T) {
+			terraformOptions := withProviderVersion(t, planTerraformOptions(t, verifier), version)
+
+			terraform.InitAndPlan(t, terraformOptions)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// expectedProviders maps each cloud to the provider its environments must
+// declare: the local name and its registry source.
+var expectedProviders = map[string]struct{ name, source string }{
+	cloudAzure: {"azurerm", "hashicorp/azurerm"},
+	cloudAWS:   {"aws", "hashicorp/aws"},
+}
+
+// parseTerraformFiles parses every .tf file in dir.
+func parseTerraformFiles(t *testing.T, dir string) []*hcl.File {
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		t.Fatalf("listing .tf files in %s: %v", dir, err)
+	}
+
+	parser := hclparse.NewParser()
+	var files []*hcl.File
+	for _, path := range paths {
+		file, diags := parser.ParseHCLFile(path)
+		if diags.HasErrors() {
+			t.Fatalf("parsing %s: %s", path, diags.Error())
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+var (
+	terraformBlockSchema = &hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "terraform"}},
+	}
+	requiredProvidersSchema = &hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "required_providers"}},
+	}
+)
+
+// requiredProviders returns the required_providers entries declared across
+// files, keyed by local provider name.
+func requiredProviders(files []*hcl.File) (map[string]cty.Value, error) {
+	providers := map[string]cty.Value{}
+	found := false
+	for _, file := range files {
+		content, _, diags := file.Body.PartialContent(terraformBlockSchema)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		for _, tfBlock := range content.Blocks {
+			tfContent, _, diags := tfBlock.Body.PartialContent(requiredProvidersSchema)
+			if diags.HasErrors() {
+				return nil, diags
+			}
+			for _, rpBlock := range tfContent.Blocks {
+				found = true
+				attrs, diags := rpBlock.Body.JustAttributes()
+				if diags.HasErrors() {
+					return nil, diags
+				}
+				for name, attr := range attrs {
+					value, diags := attr.Expr.Value(nil)
+					if diags.HasErrors() {
+						return nil, diags
+					}
+					providers[name] = value
+				}
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no required_providers block")
+	}
+	return providers, nil
+}
+
+// isPinnedConstraint reports whether a version constraint has an upper bound,
+// so a new major provider release cannot be picked up silently.
+func isPinnedConstraint(constraint string) bool {
+	for _, part := range strings.Split(constraint, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "" || part == "*":
+			continue
+		case strings.HasPrefix(part, ">"), strings.HasPrefix(part, "!="):
+			continue
+		default:
+			// "~>", "<", "<=", "=" and bare versions all bound the range.
+			return true
+		}
+	}
+	return false
+}
+
+func stringAttr(value cty.Value, name string) string {
+	if !value.Type().IsObjectType() || !value.Type().HasAttribute(name) {
+		return ""
+	}
+	attr := value.GetAttr(name)
+	if attr.IsNull() || !attr.IsKnown() || attr.Type() != cty.String {
+		return ""
+	}
+	return attr.AsString()
+}
+
+// TestRequiredProviders checks that every environment declares its cloud's
+// provider in required_providers with the expected source and a bounded
+// version.
+func TestRequiredProviders(t *testing.T) {
+	var problems []string
+	for _, env := range listEnvironments(t) {
+		providers, err := requiredProviders(parseTerraformFiles(t, environmentDir(t, env)))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", env, err))
+			continue
+		}
+
+		want := expectedProviders[cloudOf(env)]
+		provider, ok := providers[want.name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: %s missing from required_providers", env, want.name))
+			continue
+		}
+		if source := stringAttr(provider, "source"); source != want.source {
+			problems = append(problems, fmt.Sprintf("%s: %s source is %q, want %q", env, want.name, source, want.source))
+		}
+		if version := stringAttr(provider, "version"); !isPinnedConstraint(version) {
+			problems = append(problems, fmt.Sprintf("%s: %s version %q is not pinned", env, want.name, version))
+		}
+	}
+	if len(problems) > 0 {
+		t.Errorf("provider requirements not met:\n  %s", strings.Join(problems, "\n  "))
+	}
+}
@@ -0,0 +1,73 @@
+package test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+
+	"terraform-tests/steps"
+)
+
+// outputShape describes the outputs of opts by name and type, ignoring
+// their values, e.g. "location=string resource_group_name=string".
+func outputShape(t *testing.T, opts *terraform.Options) string {
+	t.Helper()
+
+	outputs := outputsJSON(t, opts)
+	shape := make([]string, 0, len(outputs))
+	for name, output := range outputs {
+		shape = append(shape, fmt.Sprintf("%s=%s", name, output.Type))
+	}
+	sort.Strings(shape)
+	return strings.Join(shape, " ")
+}
+
+// TestApplyDestroyReapply applies the environment, destroys it and applies
+// it again in the same working directory and workspace, catching lifecycle
+// bugs such as references left behind by the first destroy. Each apply uses
+// a fresh name, since Azure can keep soft-deleted resources under the old
+// one, and both must produce outputs of the same shape.
+func TestApplyDestroyReapply(t *testing.T) {
+	requireApply(t)
+	verifier := requireCloudAuth(t)
+	t.Parallel()
+
+	terraformOptions := basicTerraformOptions(t, verifier)
+	defer cleanupOnExit(t, terraformOptions)
+	trackForInterrupt(t, terraformOptions)
+	steps.Step(t, "init", func() {
+		initWorkspace(t, terraformOptions)
+	})
+
+	var firstShape string
+	var assertDestroyed func(t *testing.T)
+	steps.Step(t, "apply-1", func() {
+		terraform.Apply(t, terraformOptions)
+		firstShape = outputShape(t, terraformOptions)
+		if envFlag("TF_TEST_VERIFY_DESTROY") {
+			assertDestroyed = verifier.DestroyCheck(t, terraformOptions)
+		}
+	})
+	steps.Step(t, "destroy-1", func() {
+		terraform.Destroy(t, terraformOptions)
+		if assertDestroyed != nil {
+			assertDestroyed(t)
+		}
+	})
+
+	for key, value := range verifier.Vars(verifier.NewName()) {
+		terraformOptions.Vars[key] = value
+	}
+	steps.Step(t, "apply-2", func() {
+		if _, err := terraform.ApplyE(t, terraformOptions); err != nil {
+			t.Fatalf("reapply after destroy failed: %v", err)
+		}
+		if shape := outputShape(t, terraformOptions); shape != firstShape {
+			t.Errorf("outputs after reapply are %s, want %s", shape, firstShape)
+		}
+		verifier.AssertDeployed(t, terraformOptions)
+	})
+}
@@ -0,0 +1,143 @@
+// Package report turns the output of a test run into artifacts that CI can
+// display or track over time, such as JUnit XML and duration metrics.
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Test statuses as printed by the testing package.
+const (
+	StatusPass = "PASS"
+	StatusFail = "FAIL"
+	StatusSkip = "SKIP"
+)
+
+var (
+	headerLine = regexp.MustCompile(`^=== (RUN|CONT|NAME|PAUSE)\s+(\S+)`)
+	resultLine = regexp.MustCompile(`^--- (PASS|FAIL|SKIP): (\S+) \(([0-9.]+)s\)`)
+
+	// Under `go test -json` the testing package frames its lines with
+	// control characters, none of which are valid in XML.
+	test2jsonMarkers = strings.NewReplacer("\x0e", "", "\x0f", "", "\x16", "")
+)
+
+// TestResult is the outcome of a single test or subtest.
+type TestResult struct {
+	Name     string
+	Status   string
+	Duration time.Duration
+	Output   string
+}
+
+// Collector gathers test outcomes from verbose `go test` output. It is an
+// io.Writer so the test binary's stdout can be teed into it from TestMain;
+// see Capture.
+type Collector struct {
+	partial []byte
+	current string
+	output  map[string]*bytes.Buffer
+	results []TestResult
+}
+
+// NewCollector returns an empty Collector.
+func NewCollector() *Collector {
+	return &Collector{output: map[string]*bytes.Buffer{}}
+}
+
+// Capture redirects os.Stdout through the collector while still echoing it
+// to the original stdout. The returned function restores os.Stdout and must
+// be called before the results are read.
+func (c *Collector) Capture() (func(), error) {
+	original := os.Stdout
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("creating stdout pipe: %w", err)
+	}
+	os.Stdout = pw
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(io.MultiWriter(original, c), pr)
+	}()
+
+	return func() {
+		os.Stdout = original
+		pw.Close()
+		<-done
+		pr.Close()
+		c.flush()
+	}, nil
+}
+
+// Write parses test output line by line.
+func (c *Collector) Write(p []byte) (int, error) {
+	c.partial = append(c.partial, p...)
+	for {
+		i := bytes.IndexByte(c.partial, '\n')
+		if i < 0 {
+			break
+		}
+		c.parseLine(string(c.partial[:i]))
+		c.partial = c.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// Results returns the outcomes collected so far, in completion order.
+func (c *Collector) Results() []TestResult {
+	return c.results
+}
+
+func (c *Collector) flush() {
+	if len(c.partial) > 0 {
+		c.parseLine(string(c.partial))
+		c.partial = nil
+	}
+}
+
+func (c *Collector) parseLine(line string) {
+	line = test2jsonMarkers.Replace(line)
+	trimmed := strings.TrimLeft(line, " \t")
+
+	if m := headerLine.FindStringSubmatch(trimmed); m != nil {
+		if m[1] != "PAUSE" {
+			c.current = m[2]
+		}
+		return
+	}
+
+	if m := resultLine.FindStringSubmatch(trimmed); m != nil {
+		secs, _ := strconv.ParseFloat(m[3], 64)
+		result := TestResult{
+			Name:     m[2],
+			Status:   m[1],
+			Duration: time.Duration(secs * float64(time.Second)),
+		}
+		if buf, ok := c.output[m[2]]; ok {
+			result.Output = buf.String()
+			delete(c.output, m[2])
+		}
+		c.results = append(c.results, result)
+		return
+	}
+
+	if c.current == "" {
+		return
+	}
+	buf, ok := c.output[c.current]
+	if !ok {
+		buf = &bytes.Buffer{}
+		c.output[c.current] = buf
+	}
+	buf.WriteString(line)
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,131 @@
+package report
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parallelOutput is verbose output of two parallel tests, one with a
+// subtest, interleaved as the testing package prints them, followed by a
+// test framed with the markers `go test -json` adds.
+const parallelOutput = `=== RUN   TestA
+=== PAUSE TestA
+=== RUN   TestB
+=== PAUSE TestB
+=== CONT  TestA
+    a_test.go:4: starting A
+=== CONT  TestB
+=== RUN   TestB/sub
+    b_test.go:10: inside sub
+=== NAME  TestA
+    a_test.go:5: boom
+--- FAIL: TestA (1.50s)
+=== NAME  TestB/sub
+    b_test.go:11: more
+=== NAME  TestB
+    --- PASS: TestB/sub (0.25s)
+    b_test.go:20: skipping rest
+--- SKIP: TestB (0.30s)
+` + "\x16=== RUN   TestC\n\x16--- PASS: TestC (0.00s)\n" + `FAIL
+exit status 1`
+
+// collect feeds output to a new Collector a few bytes at a time, so lines
+// arrive split across writes as they do through a pipe.
+func collect(t *testing.T, output string) *Collector {
+	t.Helper()
+
+	c := NewCollector()
+	for len(output) > 0 {
+		n := 7
+		if n > len(output) {
+			n = len(output)
+		}
+		if _, err := c.Write([]byte(output[:n])); err != nil {
+			t.Fatal(err)
+		}
+		output = output[n:]
+	}
+	c.flush()
+	return c
+}
+
+func TestCollectorParallelOutput(t *testing.T) {
+	results := collect(t, parallelOutput).Results()
+
+	want := []struct {
+		name, status string
+		duration     time.Duration
+		output       []string
+		notOutput    []string
+	}{
+		{"TestA", StatusFail, 1500 * time.Millisecond, []string{"starting A", "a_test.go:5: boom"}, []string{"inside sub", "skipping rest"}},
+		{"TestB/sub", StatusPass, 250 * time.Millisecond, []string{"inside sub", "more"}, []string{"boom", "skipping rest"}},
+		{"TestB", StatusSkip, 300 * time.Millisecond, []string{"skipping rest"}, []string{"boom", "inside sub"}},
+		{"TestC", StatusPass, 0, nil, nil},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(results), len(want), results)
+	}
+	for i, w := range want {
+		got := results[i]
+		if got.Name != w.name || got.Status != w.status || got.Duration != w.duration {
+			t.Errorf("result %d = %s %s %s, want %s %s %s", i, got.Name, got.Status, got.Duration, w.name, w.status, w.duration)
+		}
+		for _, s := range w.output {
+			if !strings.Contains(got.Output, s) {
+				t.Errorf("%s output missing %q:\n%s", w.name, s, got.Output)
+			}
+		}
+		for _, s := range w.notOutput {
+			if strings.Contains(got.Output, s) {
+				t.Errorf("%s output has %q from another test:\n%s", w.name, s, got.Output)
+			}
+		}
+		if strings.ContainsAny(got.Output, "\x0e\x0f\x16") {
+			t.Errorf("%s output keeps test2json markers: %q", w.name, got.Output)
+		}
+	}
+}
+
+func TestJUnitReporterWriteFile(t *testing.T) {
+	reporter := NewJUnitReporterFor("suite", collect(t, parallelOutput))
+	path := filepath.Join(t.TempDir(), "report.xml")
+	if err := reporter.WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report junitTestSuites
+	if err := xml.Unmarshal(data, &report); err != nil {
+		t.Fatalf("parsing report: %v\n%s", err, data)
+	}
+	if len(report.Suites) != 1 {
+		t.Fatalf("got %d test suites, want 1", len(report.Suites))
+	}
+	suite := report.Suites[0]
+	if suite.Name != "suite" || suite.Tests != 4 || suite.Failures != 1 || suite.Skipped != 1 || suite.Time != "1.800" {
+		t.Errorf("suite = %s: %d tests, %d failures, %d skipped in %ss; want suite: 4, 1, 1 in 1.800s",
+			suite.Name, suite.Tests, suite.Failures, suite.Skipped, suite.Time)
+	}
+
+	cases := map[string]junitTestCase{}
+	for _, testCase := range suite.TestCases {
+		cases[testCase.Name] = testCase
+	}
+	if failure := cases["TestA"].Failure; failure == nil || failure.Message != "a_test.go:5: boom" {
+		t.Errorf("TestA failure = %+v, want message %q", failure, "a_test.go:5: boom")
+	}
+	if skipped := cases["TestB"].Skipped; skipped == nil || skipped.Message != "b_test.go:20: skipping rest" {
+		t.Errorf("TestB skipped = %+v, want message %q", skipped, "b_test.go:20: skipping rest")
+	}
+	if testCase := cases["TestB/sub"]; testCase.Failure != nil || testCase.Skipped != nil || testCase.Time != "0.250" {
+		t.Errorf("TestB/sub = %+v, want a pass in 0.250s", testCase)
+	}
+}
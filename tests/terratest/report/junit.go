@@ -0,0 +1,107 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JUnitReporter writes the outcomes gathered by a Collector as JUnit XML.
+type JUnitReporter struct {
+	*Collector
+	suite string
+}
+
+// NewJUnitReporter returns a reporter whose results are grouped under a
+// testsuite called suite. It collects output itself; pass it to Capture.
+func NewJUnitReporter(suite string) *JUnitReporter {
+	return NewJUnitReporterFor(suite, NewCollector())
+}
+
+// NewJUnitReporterFor returns a reporter over an existing Collector, so one
+// capture can feed several reports.
+func NewJUnitReporterFor(suite string, collector *Collector) *JUnitReporter {
+	return &JUnitReporter{Collector: collector, suite: suite}
+}
+
+// WriteFile writes the collected results to path as JUnit XML.
+func (r *JUnitReporter) WriteFile(path string) error {
+	suite := junitTestSuite{Name: r.suite}
+	var total time.Duration
+	for _, result := range r.Results() {
+		testCase := junitTestCase{
+			Name:      result.Name,
+			Classname: r.suite,
+			Time:      seconds(result.Duration),
+		}
+		switch result.Status {
+		case StatusFail:
+			suite.Failures++
+			testCase.Failure = &junitMessage{Message: lastLine(result.Output), Contents: result.Output}
+		case StatusSkip:
+			suite.Skipped++
+			testCase.Skipped = &junitMessage{Message: strings.TrimSpace(result.Output)}
+		}
+		suite.Tests++
+		suite.TestCases = append(suite.TestCases, testCase)
+		// A subtest's time is already part of its parent's.
+		if !strings.Contains(result.Name, "/") {
+			total += result.Duration
+		}
+	}
+	suite.Time = seconds(total)
+
+	data, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding JUnit report: %w", err)
+	}
+	data = append([]byte(xml.Header), append(data, '\n')...)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing JUnit report: %w", err)
+	}
+	return nil
+}
+
+// lastLine returns the final non-empty line of output, which for a failed
+// test is usually the assertion or fatal error that ended it.
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return last
+	}
+	return "Failed"
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
@@ -0,0 +1,61 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Metrics is the document written by WriteMetrics.
+type Metrics struct {
+	Environment string        `json:"environment"`
+	StartedAt   time.Time     `json:"started_at"`
+	Tests       []TestMetrics `json:"tests"`
+}
+
+// TestMetrics is the wall-clock duration and outcome of one test, with the
+// phases it recorded, if any.
+type TestMetrics struct {
+	Name            string         `json:"name"`
+	Status          string         `json:"status"`
+	DurationSeconds float64        `json:"duration_seconds"`
+	Phases          []PhaseMetrics `json:"phases,omitempty"`
+}
+
+// PhaseMetrics is the duration of one phase of a test, such as apply.
+type PhaseMetrics struct {
+	Name            string  `json:"name"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Failed          bool    `json:"failed"`
+}
+
+// NewTestMetrics converts collected results into metrics. phases, when not
+// nil, returns the phase timings recorded for a test name.
+func NewTestMetrics(results []TestResult, phases func(testName string) []PhaseMetrics) []TestMetrics {
+	metrics := make([]TestMetrics, 0, len(results))
+	for _, result := range results {
+		m := TestMetrics{
+			Name:            result.Name,
+			Status:          result.Status,
+			DurationSeconds: result.Duration.Seconds(),
+		}
+		if phases != nil {
+			m.Phases = phases(result.Name)
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics
+}
+
+// WriteMetrics writes metrics to path as indented JSON.
+func WriteMetrics(path string, metrics Metrics) error {
+	data, err := json.MarshalIndent(metrics, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding metrics: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing metrics: %w", err)
+	}
+	return nil
+}
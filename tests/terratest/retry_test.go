@@ -0,0 +1,43 @@
+package test
+
+import (
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// azureRetryableErrors are patterns for transient Azure failures that
+// terraform.WithDefaultRetryableErrors does not cover. Append to it to retry
+// on further errors.
+var azureRetryableErrors = []string{
+	".*ResourceGroupBeingDeleted.*",
+	".*RetryableError.*",
+	".*AnotherOperationInProgress.*",
+	".*StatusCode=429.*",
+	".*TooManyRequests.*",
+	".*429 Too Many Requests.*",
+}
+
+const (
+	azureMaxRetries         = 5
+	azureTimeBetweenRetries = 15 * time.Second
+)
+
+// withAzureRetryableErrors adds azureRetryableErrors to the retryable errors
+// already on opts and raises the retry budget so throttling can clear.
+func withAzureRetryableErrors(opts *terraform.Options) *terraform.Options {
+	if opts.RetryableTerraformErrors == nil {
+		opts.RetryableTerraformErrors = map[string]string{}
+	}
+	for _, pattern := range azureRetryableErrors {
+		opts.RetryableTerraformErrors[pattern] = "Transient Azure error"
+	}
+
+	if opts.MaxRetries < azureMaxRetries {
+		opts.MaxRetries = azureMaxRetries
+	}
+	if opts.TimeBetweenRetries < azureTimeBetweenRetries {
+		opts.TimeBetweenRetries = azureTimeBetweenRetries
+	}
+	return opts
+}
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/shell"
+)
+
+// checkovReport is one framework's result from `checkov -o json`.
+type checkovReport struct {
+	CheckType string `json:"check_type"`
+	Results   struct {
+		FailedChecks []checkovCheck `json:"failed_checks"`
+	} `json:"results"`
+}
+
+type checkovCheck struct {
+	CheckID   string  `json:"check_id"`
+	CheckName string  `json:"check_name"`
+	Resource  string  `json:"resource"`
+	FilePath  string  `json:"file_path"`
+	Guideline string  `json:"guideline"`
+	Severity  *string `json:"severity"`
+}
+
+func (c checkovCheck) String() string {
+	severity := "UNKNOWN"
+	if c.Severity != nil {
+		severity = *c.Severity
+	}
+	return fmt.Sprintf("[%s] %s %s (%s): %s %s", severity, c.CheckID, c.Resource, c.FilePath, c.CheckName, c.Guideline)
+}
+
+// blocking reports whether a failed check of this severity fails the build.
+func (c checkovCheck) blocking() bool {
+	if c.Severity == nil {
+		return false
+	}
+	severity := strings.ToUpper(*c.Severity)
+	return severity == "HIGH" || severity == "CRITICAL"
+}
+
+// parseCheckovOutput accepts both the single object checkov prints for one
+// framework and the array it prints for several.
+func parseCheckovOutput(output string) ([]checkovReport, error) {
+	trimmed := strings.TrimSpace(output)
+	if strings.HasPrefix(trimmed, "[") {
+		var reports []checkovReport
+		err := json.Unmarshal([]byte(trimmed), &reports)
+		return reports, err
+	}
+	var report checkovReport
+	err := json.Unmarshal([]byte(trimmed), &report)
+	return []checkovReport{report}, err
+}
+
+// TestSecurityScan runs checkov over terraform/ and fails on HIGH or CRITICAL
+// findings, logging the rest. TF_TEST_CHECKOV_SKIP takes a comma-separated
+// list of accepted check IDs to ignore. Checkov only reports severities when
+// connected to its platform; findings without one are logged.
+func TestSecurityScan(t *testing.T) {
+	requireTool(t, "checkov", "https://www.checkov.io/2.Basics/Installing%20Checkov.html")
+
+	skipped := map[string]bool{}
+	for _, id := range strings.Split(os.Getenv("TF_TEST_CHECKOV_SKIP"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			skipped[id] = true
+		}
+	}
+
+	// checkov exits non-zero when checks fail, so parse stdout first.
+	output, runErr := shell.RunCommandAndGetStdOutE(t, shell.Command{
+		Command:    "checkov",
+		Args:       []string{"-d", "terraform/", "-o", "json"},
+		WorkingDir: repoRoot,
+	})
+	reports, err := parseCheckovOutput(output)
+	if err != nil {
+		t.Fatalf("parsing checkov output (run error: %v): %v", runErr, err)
+	}
+
+	var blocking []string
+	for _, report := range reports {
+		for _, check := range report.Results.FailedChecks {
+			if skipped[check.CheckID] {
+				continue
+			}
+			if check.blocking() {
+				blocking = append(blocking, check.String())
+			} else {
+				t.Logf("checkov: %s", check)
+			}
+		}
+	}
+	if len(blocking) > 0 {
+		t.Errorf("checkov found %d high-severity finding(s):\n  %s", len(blocking), strings.Join(blocking, "\n  "))
+	}
+}
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// snapshotDir holds the committed plan snapshots.
+const snapshotDir = "testdata"
+
+// volatilePlanKeys are planned values that change between runs without the
+// configuration changing, such as IDs assigned by Azure and the creation
+// time tag.
+var volatilePlanKeys = map[string]bool{
+	"id":       true,
+	createdTag: true,
+}
+
+// planSnapshot is the part of a plan that a snapshot records: what would
+// change. The rest of the plan JSON, such as the configuration and provider
+// schema details, churns with Terraform and provider releases without the
+// diff changing.
+type planSnapshot struct {
+	ResourceChanges []resourceSnapshot        `json:"resource_changes"`
+	OutputChanges   map[string]changeSnapshot `json:"output_changes"`
+}
+
+type resourceSnapshot struct {
+	Address string `json:"address"`
+	changeSnapshot
+}
+
+type changeSnapshot struct {
+	Actions      tfjson.Actions `json:"actions"`
+	After        interface{}    `json:"after"`
+	AfterUnknown interface{}    `json:"after_unknown"`
+}
+
+// SnapshotPlan plans opts and compares its normalized resource and output
+// changes with testdata/<test name>.snapshot.json. A missing or outdated
+// snapshot fails the test; set UPDATE_SNAPSHOTS=true to write it instead,
+// then review and commit the result.
+func SnapshotPlan(t *testing.T, opts *terraform.Options) {
+	t.Helper()
+
+	opts.PlanFilePath = filepath.Join(t.TempDir(), "tfplan")
+	actual := normalizePlanJSON(t, terraform.InitAndPlanAndShow(t, opts))
+
+	path := filepath.Join(snapshotDir, strings.ReplaceAll(t.Name(), "/", "_")+".snapshot.json")
+	expected, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		if envFlag("UPDATE_SNAPSHOTS") {
+			writeSnapshot(t, path, actual)
+			t.Logf("created plan snapshot %s; commit it", path)
+			return
+		}
+		t.Fatalf("no plan snapshot %s; rerun with UPDATE_SNAPSHOTS=true to create it", path)
+	case err != nil:
+		t.Fatalf("reading snapshot: %v", err)
+	}
+
+	if string(expected) == actual {
+		return
+	}
+	if envFlag("UPDATE_SNAPSHOTS") {
+		writeSnapshot(t, path, actual)
+		t.Logf("updated plan snapshot %s", path)
+		return
+	}
+
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(expected)),
+		B:        difflib.SplitLines(actual),
+		FromFile: path,
+		ToFile:   "current plan",
+		Context:  3,
+	})
+	t.Errorf("plan differs from snapshot (rerun with UPDATE_SNAPSHOTS=true to accept):\n%s", diff)
+}
+
+// normalizePlanJSON reduces a plan to its planSnapshot, blanks out
+// volatilePlanKeys and encodes it with sorted keys so that equivalent plans
+// compare equal.
+func normalizePlanJSON(t *testing.T, planJSON string) string {
+	t.Helper()
+
+	var plan tfjson.Plan
+	if err := json.Unmarshal([]byte(planJSON), &plan); err != nil {
+		t.Fatalf("parsing plan JSON: %v", err)
+	}
+	snapshot := planSnapshot{ResourceChanges: []resourceSnapshot{}, OutputChanges: map[string]changeSnapshot{}}
+	for _, change := range plan.ResourceChanges {
+		if change.Change == nil {
+			continue
+		}
+		snapshot.ResourceChanges = append(snapshot.ResourceChanges, resourceSnapshot{
+			Address:        change.Address,
+			changeSnapshot: changeSnapshot{change.Change.Actions, change.Change.After, change.Change.AfterUnknown},
+		})
+	}
+	for name, change := range plan.OutputChanges {
+		snapshot.OutputChanges[name] = changeSnapshot{change.Actions, change.After, change.AfterUnknown}
+	}
+
+	// Round-trip through a generic value so scrubVolatile can walk it and
+	// the encoder sorts every key.
+	var generic interface{}
+	data, err := json.Marshal(snapshot)
+	if err == nil {
+		err = json.Unmarshal(data, &generic)
+	}
+	if err != nil {
+		t.Fatalf("normalizing plan JSON: %v", err)
+	}
+
+	var b strings.Builder
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(scrubVolatile(generic)); err != nil {
+		t.Fatalf("encoding plan JSON: %v", err)
+	}
+	return b.String()
+}
+
+func scrubVolatile(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			// after_unknown marks unknown values with true, which is stable.
+			switch child.(type) {
+			case string, float64:
+				if volatilePlanKeys[key] {
+					v[key] = "<volatile>"
+					continue
+				}
+			}
+			v[key] = scrubVolatile(child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = scrubVolatile(child)
+		}
+	}
+	return value
+}
+
+func writeSnapshot(t *testing.T, path, contents string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("creating snapshot directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing snapshot: %v", err)
+	}
+}
+
+// TestPlanSnapshot guards the environment's plan against unreviewed changes.
+// It runs as a subtest per environment so each keeps its own snapshot, and
+// uses a fixed resource group name so the plan is reproducible.
+func TestPlanSnapshot(t *testing.T) {
+	subscriptionID := requireAzureAuth(t)
+
+	t.Run(testEnvironment(), func(t *testing.T) {
+		terraformOptions := planTerraformOptions(t, azureVerifier{subscriptionID})
+		terraformOptions.Vars["resource_group_name"] = testEnvironment() + "-rg-terratest-snapshot"
+
+		SnapshotPlan(t, terraformOptions)
+	})
+}
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// stagedFixtureDir holds the two-stage configuration used by TestStagedApply.
+const stagedFixtureDir = "testdata/staged"
+
+// StageInputs maps the outputs of every stage applied so far to extra Vars
+// for stage, the index of the stage about to be applied.
+type StageInputs func(stage int, outputs map[string]interface{}) map[string]interface{}
+
+// StagedApply applies stages in order, for configurations such as networking
+// that must exist before the main environment. Before each stage after the
+// first, inputs is called with the merged outputs of the earlier stages and
+// its result is added to that stage's Vars. Applied stages are destroyed in
+// reverse order when the test finishes, even if a later stage fails.
+func StagedApply(t *testing.T, stages []*terraform.Options, inputs StageInputs) map[string]interface{} {
+	t.Helper()
+
+	outputs := map[string]interface{}{}
+	for i, opts := range stages {
+		if i > 0 && inputs != nil {
+			if opts.Vars == nil {
+				opts.Vars = map[string]interface{}{}
+			}
+			for name, value := range inputs(i, outputs) {
+				opts.Vars[name] = value
+			}
+		}
+
+		// Registered before applying so a half-applied stage is cleaned up.
+		// Cleanups run last-in first-out, giving reverse stage order, and
+		// tracking first keeps the stage tracked until its destroy is done.
+		opts := opts
+		trackForInterrupt(t, opts)
+		t.Cleanup(func() { destroyUnlessSkipped(t, opts) })
+
+		terraform.InitAndApply(t, opts)
+		for name, value := range terraform.OutputAll(t, opts) {
+			outputs[name] = value
+		}
+	}
+	return outputs
+}
+
+// TestStagedApply applies the two local-only stages in testdata/staged,
+// checking that the first stage's outputs reach the second and that the
+// stages are destroyed in reverse order. It creates no cloud resources.
+func TestStagedApply(t *testing.T) {
+	dir, err := files.CopyTerraformFolderToDest(stagedFixtureDir, t.TempDir(), "staged")
+	if err != nil {
+		t.Fatalf("copying %s: %v", stagedFixtureDir, err)
+	}
+	destroyLog := filepath.Join(t.TempDir(), "destroy.log")
+
+	// StagedApply destroys the stages when the subtest ends.
+	t.Run("apply", func(t *testing.T) {
+		network := &terraform.Options{
+			TerraformDir: filepath.Join(dir, "network"),
+			Vars:         map[string]interface{}{"prefix": "staged", "destroy_log": destroyLog},
+		}
+		app := &terraform.Options{
+			TerraformDir: filepath.Join(dir, "app"),
+			Vars:         map[string]interface{}{"destroy_log": destroyLog},
+		}
+
+		outputs := StagedApply(t, []*terraform.Options{network, app}, func(stage int, outputs map[string]interface{}) map[string]interface{} {
+			return map[string]interface{}{"network_name": outputs["name"]}
+		})
+		assert.Equal(t, "staged-network-app", outputs["name"])
+
+		AssertOutputSensitive(t, network, "token", true)
+		AssertOutputSensitive(t, network, "name", false)
+		assert.Equal(t, map[string]string{"prefix": "staged", "stage": "network"}, OutputMap(t, network, "settings"))
+
+		var subnets []struct {
+			Name  string `json:"name"`
+			Index int    `json:"index"`
+		}
+		OutputJSON(t, network, "subnets", &subnets)
+		if assert.Len(t, subnets, 2) {
+			assert.Equal(t, "staged-b", subnets[1].Name)
+			assert.Equal(t, 1, subnets[1].Index)
+		}
+	})
+
+	if envFlag("TF_TEST_SKIP_DESTROY") {
+		return
+	}
+	order, err := os.ReadFile(destroyLog)
+	if err != nil {
+		t.Fatalf("reading destroy log: %v", err)
+	}
+	assert.Equal(t, "app\nnetwork\n", string(order), "destroy order")
+}
@@ -0,0 +1,64 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// showState returns the current state of opts.TerraformDir as parsed by
+// `terraform show -json`.
+func showState(t *testing.T, opts *terraform.Options) *tfjson.State {
+	t.Helper()
+
+	// Show prints the plan instead of the state when a plan file is set.
+	stateOpts := *opts
+	stateOpts.PlanFilePath = ""
+
+	var state tfjson.State
+	if err := json.Unmarshal([]byte(terraform.Show(t, &stateOpts)), &state); err != nil {
+		t.Fatalf("parsing state JSON: %v", err)
+	}
+	return &state
+}
+
+// managedResources returns every managed resource instance in state,
+// including those in child modules.
+func managedResources(state *tfjson.State) []*tfjson.StateResource {
+	if state.Values == nil || state.Values.RootModule == nil {
+		return nil
+	}
+
+	var resources []*tfjson.StateResource
+	var walk func(module *tfjson.StateModule)
+	walk = func(module *tfjson.StateModule) {
+		for _, resource := range module.Resources {
+			if resource.Mode == tfjson.ManagedResourceMode {
+				resources = append(resources, resource)
+			}
+		}
+		for _, child := range module.ChildModules {
+			walk(child)
+		}
+	}
+	walk(state.Values.RootModule)
+	return resources
+}
+
+// AssertResourceCount fails the test unless state contains exactly expected
+// instances of resourceType, e.g. azurerm_resource_group.
+func AssertResourceCount(t *testing.T, opts *terraform.Options, resourceType string, expected int) {
+	t.Helper()
+
+	actual := 0
+	for _, resource := range managedResources(showState(t, opts)) {
+		if resource.Type == resourceType {
+			actual++
+		}
+	}
+	if actual != expected {
+		t.Errorf("state has %d %s instance(s), want %d", actual, resourceType, expected)
+	}
+}
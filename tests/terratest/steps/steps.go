@@ -0,0 +1,76 @@
+// Package steps marks the phases of a test, such as init, apply and destroy,
+// in its log so that failures and slow phases are easy to find.
+package steps
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Timing is the outcome of one step.
+type Timing struct {
+	Name     string
+	Duration time.Duration
+	Failed   bool
+}
+
+var (
+	mu      sync.Mutex
+	timings = map[string][]Timing{}
+)
+
+// Step runs fn as the named phase of t, logging start and end markers with
+// the elapsed time. The first step of a test registers a cleanup that logs a
+// summary of all its steps once the test finishes.
+func Step(t *testing.T, name string, fn func()) {
+	t.Helper()
+
+	mu.Lock()
+	if _, ok := timings[t.Name()]; !ok {
+		timings[t.Name()] = nil
+		t.Cleanup(func() { logSummary(t) })
+	}
+	mu.Unlock()
+
+	t.Logf("==> %s: start", name)
+	start := time.Now()
+	failedBefore := t.Failed()
+	defer func() {
+		t.Helper()
+		// Runs even when fn calls t.FailNow. Only failures raised during
+		// this step count against it.
+		timing := Timing{Name: name, Duration: time.Since(start), Failed: t.Failed() && !failedBefore}
+		mu.Lock()
+		timings[t.Name()] = append(timings[t.Name()], timing)
+		mu.Unlock()
+		t.Logf("<== %s: %s in %s", name, status(timing), timing.Duration.Round(time.Millisecond))
+	}()
+
+	fn()
+}
+
+// Timings returns the steps recorded so far for the named test.
+func Timings(testName string) []Timing {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]Timing(nil), timings[testName]...)
+}
+
+func logSummary(t *testing.T) {
+	t.Helper()
+	var b strings.Builder
+	for _, timing := range Timings(t.Name()) {
+		fmt.Fprintf(&b, "\n  %-12s %-6s %s", timing.Name, status(timing), timing.Duration.Round(time.Millisecond))
+	}
+	t.Logf("step summary:%s", b.String())
+}
+
+func status(timing Timing) string {
+	if timing.Failed {
+		return "FAILED"
+	}
+	return "ok"
+}
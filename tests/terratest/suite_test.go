@@ -0,0 +1,113 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"terraform-tests/awsverify"
+	"terraform-tests/azureverify"
+)
+
+// suiteFixture is the setup shared by every test in a run: the cloud
+// credentials check and the backend configuration from TF_BACKEND_CONFIG_FILE
+// and the TF_BACKEND_* variables. It is filled in once, by TestMain for
+// apply runs or otherwise by the first test that needs it, and is read-only
+// afterwards, so parallel tests can use it without locking.
+//
+// To add a parallel apply-based test:
+//
+//	func TestSomething(t *testing.T) {
+//		requireApply(t)
+//		verifier := requireCloudAuth(t)
+//		t.Parallel()
+//
+//		terraformOptions := basicTerraformOptions(t, verifier)
+//		defer cleanupOnExit(t, terraformOptions)
+//		trackForInterrupt(t, terraformOptions)
+//		initWorkspace(t, terraformOptions)
+//		terraform.Apply(t, terraformOptions)
+//		...
+//	}
+//
+// basicTerraformOptions gives every test its own resource name, workspace and
+// copy of the Terraform directory, so parallel tests never share state or a
+// .terraform directory. Build options with it rather than pointing
+// TerraformDir at environmentDir directly, do not call t.Setenv (which
+// panics in parallel tests), and never write to the fixture.
+type suiteFixture struct {
+	// verifier is set when the cloud's credentials work.
+	verifier cloudVerifier
+	// authErr explains why the credentials cannot be used; tests skip.
+	authErr error
+	// configErr is a misconfiguration of the run; tests fail.
+	configErr error
+	// backend is the shared backend configuration, without a state key.
+	backend map[string]interface{}
+}
+
+var (
+	suiteOnce sync.Once
+	suite     suiteFixture
+)
+
+// sharedSuite returns the fixture, preparing it on first use.
+func sharedSuite() *suiteFixture {
+	suiteOnce.Do(func() {
+		switch cloud := testCloud(); cloud {
+		case cloudAzure:
+			subscriptionID, err := azureverify.CheckAuthE()
+			suite.verifier, suite.authErr = azureVerifier{subscriptionID: subscriptionID}, err
+		case cloudAWS:
+			region, err := awsverify.CheckAuthE()
+			suite.verifier, suite.authErr = awsVerifier{region: region}, err
+		default:
+			suite.configErr = fmt.Errorf("unsupported TF_TEST_CLOUD %q, want %s or %s", cloud, cloudAzure, cloudAWS)
+		}
+		if suite.configErr != nil {
+			return
+		}
+
+		backend, err := mergeBackendConfig(nil, os.Getenv("TF_BACKEND_CONFIG_FILE"))
+		if err != nil {
+			suite.configErr = err
+		}
+		suite.backend = backend
+	})
+	return &suite
+}
+
+// requireSuite fails the test if the shared setup is misconfigured and
+// returns the fixture otherwise.
+func requireSuite(t *testing.T) *suiteFixture {
+	t.Helper()
+
+	fixture := sharedSuite()
+	if fixture.configErr != nil {
+		t.Fatal(fixture.configErr)
+	}
+	return fixture
+}
+
+// requireCloudAuth skips the test unless there are working credentials for
+// the cloud selected by TF_TEST_CLOUD, and returns its verifier. The
+// credentials are checked once per run.
+func requireCloudAuth(t *testing.T) cloudVerifier {
+	t.Helper()
+
+	fixture := requireSuite(t)
+	if fixture.authErr != nil {
+		t.Skipf("no usable %s credentials: %v", testCloud(), fixture.authErr)
+	}
+	return fixture.verifier
+}
+
+// requireAzureAuth is requireCloudAuth for Azure-only tests, returning the
+// subscription ID. Other clouds skip the test.
+func requireAzureAuth(t *testing.T) string {
+	t.Helper()
+
+	requireCloud(t, cloudAzure)
+	return requireCloudAuth(t).(azureVerifier).subscriptionID
+}
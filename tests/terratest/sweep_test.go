@@ -0,0 +1,83 @@
+package test
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"terraform-tests/azureverify"
+)
+
+// createdTag records when a test created a resource group, in RFC 3339, so
+// the sweeper can tell abandoned groups from ones still in use.
+const createdTag = "created"
+
+// defaultSweepMaxAge is how old a test resource group must be before
+// TestSweepOrphans deletes it; override with TF_TEST_SWEEP_MAX_AGE_HOURS.
+const defaultSweepMaxAge = 6 * time.Hour
+
+// TestSweepOrphans deletes resource groups left behind by failed or killed
+// runs. It is enabled with TF_TEST_SWEEP=true and removes groups whose names
+// start with TF_TEST_SWEEP_PREFIX (by default the prefix the tests in this
+// package use for the environment) and whose created tag is older than the
+// maximum age. Groups without a created tag are never touched. Set
+// TF_TEST_SWEEP_DRYRUN=true to only log what would be deleted.
+func TestSweepOrphans(t *testing.T) {
+	if !envFlag("TF_TEST_SWEEP") {
+		t.Skip("set TF_TEST_SWEEP=true to delete orphaned test resource groups")
+	}
+	subscriptionID := requireAzureAuth(t)
+
+	prefix := os.Getenv("TF_TEST_SWEEP_PREFIX")
+	if prefix == "" {
+		prefix = testEnvironment() + "-rg-terratest-"
+	}
+	maxAge := defaultSweepMaxAge
+	if hours := os.Getenv("TF_TEST_SWEEP_MAX_AGE_HOURS"); hours != "" {
+		value, err := strconv.ParseFloat(hours, 64)
+		if err != nil {
+			t.Fatalf("parsing TF_TEST_SWEEP_MAX_AGE_HOURS %q: %v", hours, err)
+		}
+		maxAge = time.Duration(value * float64(time.Hour))
+	}
+	dryRun := envFlag("TF_TEST_SWEEP_DRYRUN")
+
+	groups, err := azureverify.ListResourceGroupsE(subscriptionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	for _, group := range groups {
+		name := *group.Name
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		created, ok := group.Tags[createdTag]
+		if !ok || created == nil {
+			t.Logf("skipping %s: no %s tag", name, createdTag)
+			continue
+		}
+		createdAt, err := time.Parse(time.RFC3339, *created)
+		if err != nil {
+			t.Logf("skipping %s: unparseable %s tag %q", name, createdTag, *created)
+			continue
+		}
+		if createdAt.After(cutoff) {
+			continue
+		}
+
+		age := time.Since(createdAt).Round(time.Minute)
+		if dryRun {
+			t.Logf("dry run: would delete %s (created %s ago)", name, age)
+			continue
+		}
+		t.Logf("deleting %s (created %s ago)", name, age)
+		if err := azureverify.DeleteResourceGroupE(subscriptionID, name); err != nil {
+			t.Errorf("deleting %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+
+	"terraform-tests/azureverify"
+)
+
+// withTargets limits plan, apply and destroy to the given resource
+// addresses. Targeted runs leave the rest of the configuration unapplied, so
+// the resulting state is partial; use it for fast local iteration only.
+func withTargets(opts *terraform.Options, targets []string) *terraform.Options {
+	opts.Targets = append(opts.Targets, targets...)
+	return opts
+}
+
+// TestTargetedApply applies only the environment's resource group.
+func TestTargetedApply(t *testing.T) {
+	requireApply(t)
+	subscriptionID := requireAzureAuth(t)
+	t.Parallel()
+
+	terraformOptions := withTargets(basicTerraformOptions(t, azureVerifier{subscriptionID}), []string{"module.example.azurerm_resource_group.main"})
+	t.Logf("WARNING: targeted apply of %s produces partial state", strings.Join(terraformOptions.Targets, ", "))
+
+	defer cleanupOnExit(t, terraformOptions)
+	trackForInterrupt(t, terraformOptions)
+	initWorkspace(t, terraformOptions)
+	terraform.Apply(t, terraformOptions)
+
+	resourceGroupName := terraform.Output(t, terraformOptions, "resource_group_name")
+	azureverify.AssertResourceGroupExists(t, subscriptionID, resourceGroupName)
+}
@@ -0,0 +1,30 @@
+# Second stage of TestStagedApply, fed the first stage's name.
+terraform {
+  required_version = ">= 1.4"
+}
+
+variable "network_name" {
+  description = "Name output by the network stage"
+  type        = string
+}
+
+variable "destroy_log" {
+  description = "File each stage appends its name to when destroyed"
+  type        = string
+}
+
+resource "terraform_data" "stage" {
+  input = {
+    name = "${var.network_name}-app"
+    log  = var.destroy_log
+  }
+
+  provisioner "local-exec" {
+    when    = destroy
+    command = "echo app >> '${self.input.log}'"
+  }
+}
+
+output "name" {
+  value = terraform_data.stage.output.name
+}
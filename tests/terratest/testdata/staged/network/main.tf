@@ -0,0 +1,50 @@
+# First stage of TestStagedApply. terraform_data needs no provider, so the
+# test runs without credentials.
+terraform {
+  required_version = ">= 1.4"
+}
+
+variable "prefix" {
+  description = "Prefix for the stage's name"
+  type        = string
+}
+
+variable "destroy_log" {
+  description = "File each stage appends its name to when destroyed"
+  type        = string
+}
+
+resource "terraform_data" "stage" {
+  input = {
+    name = "${var.prefix}-network"
+    log  = var.destroy_log
+  }
+
+  provisioner "local-exec" {
+    when    = destroy
+    command = "echo network >> '${self.input.log}'"
+  }
+}
+
+output "name" {
+  value = terraform_data.stage.output.name
+}
+
+output "settings" {
+  value = {
+    prefix = var.prefix
+    stage  = "network"
+  }
+}
+
+output "subnets" {
+  value = [
+    { name = "${var.prefix}-a", index = 0 },
+    { name = "${var.prefix}-b", index = 1 },
+  ]
+}
+
+output "token" {
+  value     = "token-${var.prefix}"
+  sensitive = true
+}
@@ -0,0 +1,165 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	terratesting "github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// defaultTestTimeout applies when neither the caller nor TF_TEST_TIMEOUT sets
+// a deadline. It is kept below the 30m `go test -timeout` used in CI so a
+// hung apply still gets a chance to clean up.
+const defaultTestTimeout = 20 * time.Minute
+
+// testTimeout returns the TF_TEST_TIMEOUT duration, such as "30m", or
+// defaultTestTimeout.
+func testTimeout(t *testing.T) time.Duration {
+	t.Helper()
+
+	raw := os.Getenv("TF_TEST_TIMEOUT")
+	if raw == "" {
+		return defaultTestTimeout
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		t.Fatalf("parsing TF_TEST_TIMEOUT %q: %v", raw, err)
+	}
+	return d
+}
+
+// timeoutGrace is how long WithTimeout waits, after the deadline, for fn to
+// return before destroying without it.
+const timeoutGrace = 2 * time.Minute
+
+// WithTimeout runs fn and fails the test if it has not returned within d,
+// or within testTimeout when d is zero. fn must report failures through the
+// TestingT it is given rather than t, since after a timeout it may outlive
+// the test; they are reported on t once fn returns.
+//
+// fn cannot be stopped, so on timeout WithTimeout waits up to timeoutGrace
+// for it to return and then destroys each of cleanup on a best-effort basis.
+// If fn is still running, its apply may be writing state, so each destroy
+// takes the state lock and waits up to its LockTimeout for the apply to
+// release it; applies from environmentOptions take the lock too.
+func WithTimeout(t *testing.T, d time.Duration, fn func(t terratesting.TestingT), cleanup ...*terraform.Options) {
+	t.Helper()
+
+	if d <= 0 {
+		d = testTimeout(t)
+	}
+
+	recorder := &recordingT{name: t.Name()}
+	done := make(chan struct{})
+	panicked := make(chan interface{}, 1)
+	go func() {
+		// FailNow in fn exits this goroutine, so always signal completion.
+		defer close(done)
+		defer func() {
+			if r := recover(); r != nil {
+				panicked <- r
+			}
+		}()
+		fn(recorder)
+	}()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		select {
+		case r := <-panicked:
+			// Re-panic on the test goroutine so deferred cleanups run.
+			panic(r)
+		default:
+		}
+		if recorder.reportTo(t) {
+			t.FailNow()
+		}
+	case <-timer.C:
+		t.Errorf("timed out after %s", d)
+		select {
+		case <-done:
+			recorder.reportTo(t)
+		case <-time.After(timeoutGrace):
+			t.Errorf("still running %s after the timeout, destroying once it releases the state lock", timeoutGrace)
+		}
+		for _, opts := range cleanup {
+			if _, err := terraform.DestroyE(t, withStateLock(opts)); err != nil {
+				t.Logf("best-effort destroy of %s failed: %v", opts.TerraformDir, err)
+			}
+		}
+		t.FailNow()
+	}
+}
+
+// recordingT is the TestingT WithTimeout gives fn. It records failures
+// instead of reporting them on the test, which fn may outlive.
+type recordingT struct {
+	name string
+
+	mu       sync.Mutex
+	failed   bool
+	messages []string
+}
+
+func (r *recordingT) Fail() {
+	r.mu.Lock()
+	r.failed = true
+	r.mu.Unlock()
+}
+
+func (r *recordingT) FailNow() {
+	r.Fail()
+	runtime.Goexit()
+}
+
+func (r *recordingT) Fatal(args ...interface{}) {
+	r.Error(args...)
+	r.FailNow()
+}
+
+func (r *recordingT) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+	r.FailNow()
+}
+
+func (r *recordingT) Error(args ...interface{}) {
+	r.record(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.record(fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Name() string { return r.name }
+
+func (r *recordingT) record(message string) {
+	r.mu.Lock()
+	r.failed = true
+	r.messages = append(r.messages, message)
+	r.mu.Unlock()
+}
+
+// reportTo reports the recorded failures on t and returns whether there
+// were any.
+func (r *recordingT) reportTo(t *testing.T) bool {
+	t.Helper()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, message := range r.messages {
+		t.Error(message)
+	}
+	if r.failed && len(r.messages) == 0 {
+		t.Fail()
+	}
+	return r.failed
+}
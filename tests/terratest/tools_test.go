@@ -0,0 +1,18 @@
+package test
+
+import (
+	"os/exec"
+	"testing"
+)
+
+// repoRoot is the repository root, relative to this package.
+const repoRoot = "../.."
+
+// requireTool skips the test when the named binary is not on PATH.
+func requireTool(t *testing.T, name, installURL string) {
+	t.Helper()
+
+	if _, err := exec.LookPath(name); err != nil {
+		t.Skipf("%s is not installed; see %s", name, installURL)
+	}
+}
@@ -0,0 +1,56 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// AssertVarValidationFails plans opts with badVars merged over its Vars and
+// fails the test unless the plan is rejected with an error containing
+// wantErrSubstr, typically a validation block's error_message. Whitespace is
+// compared loosely because Terraform wraps long messages. opts is not
+// modified.
+func AssertVarValidationFails(t *testing.T, opts *terraform.Options, badVars map[string]interface{}, wantErrSubstr string) {
+	t.Helper()
+
+	badOptions := *opts
+	badOptions.Vars = make(map[string]interface{}, len(opts.Vars)+len(badVars))
+	for key, value := range opts.Vars {
+		badOptions.Vars[key] = value
+	}
+	for key, value := range badVars {
+		badOptions.Vars[key] = value
+	}
+
+	_, err := terraform.InitAndPlanE(t, &badOptions)
+	if err == nil {
+		t.Errorf("plan with %v succeeded, want a validation error containing %q", badVars, wantErrSubstr)
+		return
+	}
+	if !strings.Contains(collapseSpace(err.Error()), collapseSpace(wantErrSubstr)) {
+		t.Errorf("plan with %v failed without %q:\n%v", badVars, wantErrSubstr, err)
+	}
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// TestVariableValidation checks that the module's validation blocks reject
+// inputs outside our guardrails.
+func TestVariableValidation(t *testing.T) {
+	subscriptionID := requireAzureAuth(t)
+
+	terraformOptions := planTerraformOptions(t, azureVerifier{subscriptionID})
+
+	t.Run("location", func(t *testing.T) {
+		AssertVarValidationFails(t, terraformOptions, map[string]interface{}{"location": "Mars Central"},
+			"location must be one of the approved regions")
+	})
+	t.Run("resource_group_name", func(t *testing.T) {
+		AssertVarValidationFails(t, terraformOptions, map[string]interface{}{"resource_group_name": strings.Repeat("a", maxResourceGroupNameLength+1)},
+			"resource_group_name must be 1 to 90 characters long.")
+	})
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+var (
+	variableBlockSchema = &hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "variable", LabelNames: []string{"name"}}},
+	}
+	variableAttrSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "description"}, {Name: "type"}, {Name: "default"}},
+	}
+)
+
+// variableDecl records which documentation attributes a variable declares.
+type variableDecl struct {
+	Name           string
+	HasDescription bool
+	HasType        bool
+	HasDefault     bool
+}
+
+// declaredVariables returns the variable blocks declared in files.
+func declaredVariables(t *testing.T, files []*hcl.File) []variableDecl {
+	t.Helper()
+
+	var vars []variableDecl
+	for _, file := range files {
+		content, _, diags := file.Body.PartialContent(variableBlockSchema)
+		if diags.HasErrors() {
+			t.Fatalf("reading variable blocks: %s", diags.Error())
+		}
+		for _, block := range content.Blocks {
+			attrs, _, diags := block.Body.PartialContent(variableAttrSchema)
+			if diags.HasErrors() {
+				t.Fatalf("reading variable %q: %s", block.Labels[0], diags.Error())
+			}
+			_, hasDescription := attrs.Attributes["description"]
+			_, hasType := attrs.Attributes["type"]
+			_, hasDefault := attrs.Attributes["default"]
+			vars = append(vars, variableDecl{
+				Name:           block.Labels[0],
+				HasDescription: hasDescription,
+				HasType:        hasType,
+				HasDefault:     hasDefault,
+			})
+		}
+	}
+	return vars
+}
+
+// fixtureVars returns, keyed by environment, the variables supplied by the
+// fixtures for that environment.
+func fixtureVars(t *testing.T) map[string]map[string]bool {
+	t.Helper()
+
+	supplied := map[string]map[string]bool{}
+	for _, path := range fixtureFiles(t) {
+		tc, err := LoadTestCase(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env := tc.environment()
+		if supplied[env] == nil {
+			supplied[env] = map[string]bool{}
+		}
+		for name := range tc.Vars {
+			supplied[env][name] = true
+		}
+	}
+	return supplied
+}
+
+// TestVariableDocumentation requires every environment variable to declare a
+// description and a type, and every variable without a default to be
+// supplied by at least one fixture.
+func TestVariableDocumentation(t *testing.T) {
+	supplied := fixtureVars(t)
+
+	var problems []string
+	for _, env := range listEnvironments(t) {
+		dir := environmentDir(t, env)
+		for _, v := range declaredVariables(t, parseTerraformFiles(t, dir)) {
+			var missing []string
+			if !v.HasDescription {
+				missing = append(missing, "description")
+			}
+			if !v.HasType {
+				missing = append(missing, "type")
+			}
+			if len(missing) > 0 {
+				problems = append(problems, fmt.Sprintf("%s: variable %q has no %s", env, v.Name, strings.Join(missing, " or ")))
+			}
+			if !v.HasDefault && !supplied[env][v.Name] {
+				problems = append(problems, fmt.Sprintf("%s: variable %q has no default and no fixture supplies it", env, v.Name))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		t.Errorf("variable documentation problems:\n  %s", strings.Join(problems, "\n  "))
+	}
+}
@@ -0,0 +1,58 @@
+package test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// workspaces maps options to the Terraform workspace they run in. The
+// terratest version we pin has no Options.Workspace field, so the selection
+// is tracked here instead.
+var workspaces sync.Map // *terraform.Options -> string
+
+// withWorkspace runs opts in the workspace name, keeping its state apart
+// from other tests sharing the same backend key. Pass a per-test value, such
+// as a name from uniqueName. The workspace is selected, or created, by
+// initWorkspace and deleted by cleanupOnExit after destroy.
+func withWorkspace(opts *terraform.Options, name string) *terraform.Options {
+	workspaces.Store(opts, name)
+	return opts
+}
+
+// workspaceFor returns the workspace set on opts by withWorkspace, if any.
+func workspaceFor(opts *terraform.Options) (string, bool) {
+	name, ok := workspaces.Load(opts)
+	if !ok {
+		return "", false
+	}
+	return name.(string), true
+}
+
+// initWorkspace runs terraform init and then switches to the workspace set
+// by withWorkspace, creating it if needed.
+func initWorkspace(t *testing.T, opts *terraform.Options) {
+	t.Helper()
+
+	terraform.Init(t, opts)
+	if name, ok := workspaceFor(opts); ok {
+		terraform.WorkspaceSelectOrNew(t, opts, name)
+	}
+}
+
+// deleteWorkspace removes the workspace set by withWorkspace. Terraform
+// refuses to delete a workspace that still tracks resources, so a failed
+// destroy leaves it in place to be cleaned up by hand.
+func deleteWorkspace(t *testing.T, opts *terraform.Options) {
+	t.Helper()
+
+	name, ok := workspaceFor(opts)
+	if !ok {
+		return
+	}
+	defer workspaces.Delete(opts)
+	if _, err := terraform.WorkspaceDeleteE(t, opts, name); err != nil {
+		t.Errorf("deleting workspace %s: %v", name, err)
+	}
+}